@@ -1,23 +1,91 @@
 package main
 
 import (
-	"github.com/1Password/dep-report/models"
-	"github.com/1Password/dep-report/parse"
-	"github.com/1Password/dep-report/report"
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"time"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/1Password/dep-report/parse"
+	"github.com/1Password/dep-report/report"
+	"github.com/1Password/dep-report/versioncontrol"
+	"github.com/pkg/errors"
 )
 
 const (
-	depFilePath   = "/Gopkg.lock"
-	goModFilePath = "/go.mod"
+	depFilePath    = "/Gopkg.lock"
+	goModFilePath  = "/go.mod"
+	goSumFilePath  = "/go.sum"
+	vendorFilePath = "/vendor/modules.txt"
+)
+
+// Dependency files selectable with -input
+const (
+	inputAuto   = "auto"
+	inputGopkg  = "gopkg"
+	inputGoMod  = "gomod"
+	inputGoWork = "gowork"
+	inputVendor = "vendor"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "why" {
+		runWhy(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "plan" {
+		runPlan(os.Args[2:])
+		return
+	}
+
+	workers := flag.Int("workers", 8, "number of dependencies resolved concurrently")
+	hostConcurrency := flag.Int("host-concurrency", 4, "maximum number of dependencies resolved concurrently against one host, 0 for no limit")
+	onFailure := flag.String("on-failure", "fail-fast", "what to do when a dependency cannot be resolved: fail-fast, best-effort or fail-at-end")
+	maxRateLimitWait := flag.Duration("max-rate-limit-wait", time.Minute, "longest time to wait for a rate limit to reset before failing")
+	cacheDir := flag.String("cache-dir", "", "directory caching API responses between runs, no caching when empty")
+	cacheTTL := flag.Duration("cache-ttl", time.Hour, "how long cached responses that may change are used before revalidating them")
+	cacheOnly := flag.Bool("cache-only", false, "serve every response from -cache-dir and never use the network")
+	offline := flag.Bool("offline", false, "resolve dependencies from the local module cache only, without network access")
+	directOnly := flag.Bool("direct-only", false, "only report dependencies that go.mod does not mark as indirect")
+	excludePrereleases := flag.Bool("exclude-prereleases", false, "never report a prerelease tag as the latest version")
+	all := flag.Bool("all", false, "report the full build list from go mod graph instead of only the requirements in go.mod")
+	why := flag.Bool("why", false, "include the shortest requirement chains from the main module to each dependency")
+	input := flag.String("input", inputAuto, "dependency file to read: auto, gopkg, gomod, gowork or vendor")
+	binary := flag.String("binary", "", "report on the dependencies embedded in this Go executable instead of a dependency file")
+	verifySums := flag.Bool("verify-sums", false, "verify go.sum hashes against the checksum database configured by GOSUMDB")
+	recursive := flag.Bool("recursive", false, "report on every module below the working directory, one report per module")
+	skip := flag.String("skip", "", "comma separated globs of directories that -recursive skips, besides vendor and testdata")
+	outputDir := flag.String("output-dir", "", "with -recursive, write one report file per module to this directory instead of printing a combined document")
+	flag.Parse()
+
+	failureMode, err := report.ParseFailureMode(*onFailure)
+	if err != nil {
+		log.Fatalf("invalid -on-failure: %v", err)
+	}
+	err = checkFlags(flagValues{
+		cacheDir:   *cacheDir,
+		cacheOnly:  *cacheOnly,
+		verifySums: *verifySums,
+		offline:    *offline,
+		recursive:  *recursive,
+		skip:       *skip,
+		outputDir:  *outputDir,
+		binary:     *binary,
+		input:      *input,
+		all:        *all,
+		why:        *why,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	githubToken := os.Getenv("GITHUB_OAUTH_TOKEN")
-	if githubToken == "" {
+	if githubToken == "" && !*offline {
 		log.Fatal("missing argument: GitHub Token")
 	}
 
@@ -27,16 +95,61 @@ func main() {
 		productName = "b5server"
 	}
 
-	dependencies, err := getDependencyFile()
-	if err != nil {
-		log.Fatalf("unable to parse dependency file: %v", err)
+	g := report.NewGenerator(githubToken, productName)
+	if err := g.Registry().RegisterHostsFromEnv(); err != nil {
+		log.Fatalf("unable to configure providers: %v", err)
+	}
+	g.Workers = *workers
+	g.HostConcurrency = *hostConcurrency
+	g.FailureMode = failureMode
+	g.MaxRateLimitWait = *maxRateLimitWait
+	g.Offline = *offline
+	g.DirectOnly = *directOnly
+	g.ExcludePrereleases = *excludePrereleases
+	if *verifySums {
+		sumDB := versioncontrol.SumDBFromEnv()
+		g.SumDB = &sumDB
+	}
+	if *cacheDir != "" {
+		g.Cache = &versioncontrol.Cache{Dir: *cacheDir, TTL: *cacheTTL, Offline: *cacheOnly}
 	}
 
-	g := report.NewGenerator(githubToken, productName)
+	// Stop outstanding lookups on the first interrupt
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
 
-	rawReport, err := g.BuildReport(productName, dependencies)
+	if *recursive {
+		err := runRecursive(ctx, g, productName, recursiveOptions{
+			skip:      splitList(*skip),
+			outputDir: *outputDir,
+			all:       *all,
+			why:       *why,
+		})
+		if err != nil {
+			log.Fatalf("unable to generate reports: %v", err)
+		}
+		return
+	}
+
+	var dependencies []models.Dependency
+	if *binary != "" {
+		dependencies, err = getBinaryDependencies(g, *binary)
+	} else {
+		dependencies, err = getDependencyFile(*input, *all, *why)
+	}
 	if err != nil {
-		log.Fatalf("unable to generate report: %v", err)
+		log.Fatalf("unable to parse dependency file: %v", err)
+	}
+
+	rawReport, buildErr := g.BuildReportContext(ctx, productName, dependencies)
+	if buildErr != nil && errors.Cause(buildErr) != report.ErrDependenciesFailed {
+		log.Fatalf("unable to generate report: %v", buildErr)
 	}
 
 	prettyReport, err := report.FormatReport(*rawReport)
@@ -44,31 +157,172 @@ func main() {
 		log.Fatalf("unable to format report: %v", err)
 	}
 	fmt.Println(string(prettyReport))
+
+	// In fail-at-end mode the report is still printed before exiting with an error
+	if buildErr != nil {
+		log.Fatalf("unable to generate report: %v", buildErr)
+	}
+}
+
+// flagValues holds the flags that depend on or rule out each other
+type flagValues struct {
+	cacheDir   string
+	cacheOnly  bool
+	verifySums bool
+	offline    bool
+	recursive  bool
+	skip       string
+	outputDir  string
+	binary     string
+	input      string
+	all        bool
+	why        bool
 }
 
-func getDependencyFile() ([]models.Dependency, error) {
+// checkFlags returns an error for flags missing the flags they need or combined with flags they rule out
+func checkFlags(f flagValues) error {
+	switch {
+	case f.cacheOnly && f.cacheDir == "":
+		return errors.New("-cache-only requires -cache-dir")
+	case f.verifySums && f.offline:
+		return errors.New("-verify-sums needs network access and cannot be combined with -offline")
+	case !f.recursive && (f.skip != "" || f.outputDir != ""):
+		return errors.New("-skip and -output-dir require -recursive")
+	case f.recursive && f.binary != "":
+		return errors.New("-recursive cannot be combined with -binary")
+	case f.recursive && f.input != inputAuto && f.input != inputGoMod:
+		return errors.New("-recursive only reads go.mod files")
+	case f.binary != "" && (f.input != inputAuto || f.all || f.why):
+		return errors.New("-binary cannot be combined with -input, -all or -why")
+	}
+	return nil
+}
+
+func getDependencyFile(input string, all, why bool) ([]models.Dependency, error) {
 	wd, err := os.Getwd()
 	if err != nil {
 		return nil, err
 	}
-	var deps []models.Dependency
+	if input == inputAuto {
+		input = detectInput(wd)
+	}
 
-	switch {
-	case fileExists(filepath.Join(wd, depFilePath)):
+	switch input {
+	case inputGopkg:
 		pkg, err := parse.ReadGopkg(wd + depFilePath)
 		if err != nil {
 			return nil, err
 		}
-		deps = parse.MapPkgToDependency(*pkg)
-	case fileExists(filepath.Join(wd, goModFilePath)):
-		mods, err := parse.ParseModules(filepath.Join(wd, goModFilePath))
+		return parse.MapPkgToDependency(*pkg), nil
+	case inputGoWork:
+		if all || why {
+			return nil, errors.New("-all and -why are not supported for go.work workspaces")
+		}
+		workPath, ok := parse.FindWorkspace(wd)
+		if !ok {
+			return nil, errors.Errorf("no go.work found for %s", wd)
+		}
+		mods, err := parse.ParseWorkspace(workPath)
+		if err != nil {
+			return nil, err
+		}
+		sums, err := parse.ReadWorkspaceGoSum(workPath)
 		if err != nil {
 			return nil, err
 		}
-		deps = parse.MapModToDependency(mods)
+		sums.Apply(mods)
+		return parse.MapModToDependency(mods), nil
+	case inputGoMod:
+		return getModuleDependencies(wd, all, why)
+	case inputVendor:
+		if all || why {
+			return nil, errors.New("-all and -why are not supported for vendor/modules.txt")
+		}
+		mods, err := parse.ParseVendor(wd + vendorFilePath)
+		if err != nil {
+			return nil, err
+		}
+		sums, err := parse.ReadGoSum(wd + goSumFilePath)
+		if err != nil {
+			return nil, err
+		}
+		sums.Apply(mods)
+		return parse.MapModToDependency(mods), nil
+	case "":
+		return nil, nil
+	default:
+		return nil, errors.Errorf("unknown input %q", input)
+	}
+}
+
+// detectInput picks the dependency file in dir like earlier versions did: Gopkg.lock, then go.work, then go.mod.
+// vendor/modules.txt is only read when selected explicitly.
+func detectInput(dir string) string {
+	_, inWorkspace := parse.FindWorkspace(dir)
+	switch {
+	case fileExists(filepath.Join(dir, depFilePath)):
+		return inputGopkg
+	case inWorkspace:
+		return inputGoWork
+	case fileExists(filepath.Join(dir, goModFilePath)):
+		return inputGoMod
 	}
+	return ""
+}
 
-	return deps, nil
+// getBinaryDependencies reads the dependencies embedded in a Go executable, and reports the commit it was built from
+// rather than the one of the working directory
+func getBinaryDependencies(g *report.Generator, path string) ([]models.Dependency, error) {
+	binary, err := parse.ReadBinary(path)
+	if err != nil {
+		return nil, err
+	}
+
+	g.Commit = &report.Commit{Hash: binary.Commit, Time: binary.CommitTime}
+	g.Build = &models.Build{
+		GoVersion: binary.GoVersion,
+		Main:      models.ModuleVersion{Path: binary.Main.Path, Version: binary.Main.Version},
+	}
+	switch {
+	case binary.Commit == "":
+		log.Printf("%s does not record the commit it was built from", path)
+	case binary.Modified:
+		log.Printf("%s was built from a modified working tree at %s", path, binary.Commit)
+	}
+	return parse.MapModToDependency(binary.Modules), nil
+}
+
+// getModuleDependencies reads the dependencies of the module in dir from its go.mod, or from its module graph
+// when the full build list or requirement chains are wanted
+func getModuleDependencies(dir string, all, why bool) ([]models.Dependency, error) {
+	var graph *parse.ModuleGraph
+	var err error
+	if all || why {
+		graph, err = parse.ReadModGraph(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var mods []models.Module
+	if all {
+		mods, err = parse.ParseBuildList(filepath.Join(dir, goModFilePath), graph)
+	} else {
+		mods, err = parse.ParseModules(filepath.Join(dir, goModFilePath))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if why {
+		graph.Explain(mods)
+	}
+
+	sums, err := parse.ReadGoSum(filepath.Join(dir, goSumFilePath))
+	if err != nil {
+		return nil, err
+	}
+	sums.Apply(mods)
+	return parse.MapModToDependency(mods), nil
 }
 
 func fileExists(filename string) bool {
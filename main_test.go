@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFlags(t *testing.T) {
+	tests := []struct {
+		description string
+		flags       flagValues
+		wantError   string
+	}{
+		{
+			description: "should accept the defaults",
+			flags:       flagValues{input: inputAuto},
+		},
+		{
+			description: "should accept -recursive with its options",
+			flags:       flagValues{input: inputGoMod, recursive: true, skip: "tools/*", outputDir: "reports", all: true, why: true},
+		},
+		{
+			description: "should accept -binary on its own",
+			flags:       flagValues{input: inputAuto, binary: "dep-report"},
+		},
+		{
+			description: "should require -cache-dir for -cache-only",
+			flags:       flagValues{input: inputAuto, cacheOnly: true},
+			wantError:   "-cache-only requires -cache-dir",
+		},
+		{
+			description: "should not verify sums offline",
+			flags:       flagValues{input: inputAuto, verifySums: true, offline: true},
+			wantError:   "-verify-sums needs network access and cannot be combined with -offline",
+		},
+		{
+			description: "should require -recursive for -output-dir",
+			flags:       flagValues{input: inputAuto, outputDir: "reports"},
+			wantError:   "-skip and -output-dir require -recursive",
+		},
+		{
+			description: "should require -recursive for -skip",
+			flags:       flagValues{input: inputAuto, skip: "tools/*"},
+			wantError:   "-skip and -output-dir require -recursive",
+		},
+		{
+			description: "should not combine -recursive with -binary",
+			flags:       flagValues{input: inputAuto, recursive: true, binary: "dep-report"},
+			wantError:   "-recursive cannot be combined with -binary",
+		},
+		{
+			description: "should only read go.mod files with -recursive",
+			flags:       flagValues{input: inputVendor, recursive: true},
+			wantError:   "-recursive only reads go.mod files",
+		},
+		{
+			description: "should not combine -binary with -why",
+			flags:       flagValues{input: inputAuto, binary: "dep-report", why: true},
+			wantError:   "-binary cannot be combined with -input, -all or -why",
+		},
+		{
+			description: "should not combine -binary with -input",
+			flags:       flagValues{input: inputGoMod, binary: "dep-report"},
+			wantError:   "-binary cannot be combined with -input, -all or -why",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := checkFlags(test.flags)
+			if test.wantError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.wantError)
+			}
+		})
+	}
+}
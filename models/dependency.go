@@ -6,10 +6,53 @@ type Dependency struct {
 	//Revision is the commit SHA from gopkg or the version from go.mod.
 	//3 formats are used here: full 40 char commit SHA(gopkg), 12 char commit SHA prefix(go.mod) or semantic version number(go.mod)
 	Revision string
-	//Name is the name of the dependency and comes from Name in gopkg and Path in go.mod
+	//Name is the name of the dependency and comes from Name in gopkg and Path in go.mod, including any major version suffix
 	Name string
+	//Path is the full module path from go.mod, including any major version suffix. It is empty for gopkg dependencies
+	Path string
+	//Root is the module path without its /vN major version suffix, which the repository is looked up under.
+	//It is empty for gopkg dependencies, whose Name already is the repository root
+	Root string
 	// Version of the installed dependency
 	Version string
+	//BaseVersion is the release a pseudo-version builds on, empty for other versions and for pseudo-versions
+	//of commits that follow no release
+	BaseVersion string
+	//CommitTime is the commit time a pseudo-version encodes, in UTC. It is empty for other versions
+	CommitTime string
+	//Subdir is the directory of the module in its repository, which prefixes its version tags, e.g. sub/module/v1.2.3
+	Subdir string
+	//Repo is the URL of the repository hosting the dependency, resolved from the package maps or go-get meta tags
+	Repo string
+	//Indirect is set for go.mod requirements marked // indirect, which are not imported by the main module itself
+	Indirect bool
+	//RequiredBy lists the module versions, as path@version, that require the dependency in the module graph
+	RequiredBy []string
+	//Why holds the shortest requirement chains from the main module to the dependency
+	Why [][]string
+	//UsedBy lists the modules of a go.work workspace that require the dependency
+	UsedBy []string
+	//Implicit is set for modules in vendor/modules.txt that go.mod does not require
+	Implicit bool
+	//Packages lists the packages vendored from the dependency
+	Packages []string
+	//Sum is the h1: hash of the module contents
+	Sum string
+	//GoModSum is the h1: hash of the go.mod file of the module
+	GoModSum string
+	//Warnings point out problems found in the dependency files, such as a module missing from go.sum
+	Warnings []string
+	//Replace is the dependency resolved in place of this one because of a replace directive in go.mod.
+	//Its Version is empty when the replacement is a directory on disk
+	Replace *Dependency
+}
+
+// RootPath returns the path the repository of the dependency is looked up under
+func (d Dependency) RootPath() string {
+	if d.Root != "" {
+		return d.Root
+	}
+	return d.Name
 }
 
 // PkgObject Objects used when reading from Gopkg.lock
@@ -30,4 +73,24 @@ type Pkg struct {
 type Module struct {
 	Path    string
 	Version string
+	// Indirect is set when go.mod marks the requirement // indirect
+	Indirect bool
+	// RequiredBy lists the module versions, as path@version, that require this module in the module graph
+	RequiredBy []string
+	// Why holds the shortest requirement chains from the main module to this module
+	Why [][]string
+	// UsedBy lists the modules of a go.work workspace that require this module
+	UsedBy []string
+	// Implicit is set when vendor/modules.txt lists the module without a `## explicit` marker
+	Implicit bool
+	// Packages lists the packages vendor/modules.txt vendors from the module
+	Packages []string
+	// Sum is the h1: hash of the module contents, as recorded in go.sum or in the build info of a binary
+	Sum string
+	// GoModSum is the h1: hash of the go.mod file of the module, as recorded in go.sum
+	GoModSum string
+	// Warnings point out problems found in the dependency files, such as a module missing from go.sum
+	Warnings []string
+	// Replace is the module replacing this one, with an empty Version for a directory on disk
+	Replace *Module
 }
@@ -26,10 +26,89 @@ type BranchInfo struct {
 	Revision string `json:"revision"`
 }
 
-type Release struct {
-	Name string `json:"tag_name"`
+type GithubTag struct {
+	Name string `json:"name"`
 }
 
 type Tag struct {
 	Ref string `json:"ref"`
 }
+
+// ModuleInfo is the json returned by the GOPROXY protocol `.info` and `@latest` endpoints
+type ModuleInfo struct {
+	Version string        `json:"Version"`
+	Time    string        `json:"Time"`
+	Origin  *ModuleOrigin `json:"Origin,omitempty"`
+}
+
+// ModuleOrigin describes the VCS revision a module version was fetched from, when the proxy provides it
+type ModuleOrigin struct {
+	VCS  string `json:"VCS"`
+	URL  string `json:"URL"`
+	Ref  string `json:"Ref"`
+	Hash string `json:"Hash"`
+}
+
+type GitlabLicense struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+type GitlabProject struct {
+	ID            int            `json:"id"`
+	DefaultBranch string         `json:"default_branch"`
+	License       *GitlabLicense `json:"license"`
+}
+
+type GitlabCommit struct {
+	ID            string `json:"id"`
+	CommittedDate string `json:"committed_date"`
+}
+
+type GitlabTag struct {
+	Name string `json:"name"`
+}
+
+type BitbucketRef struct {
+	Name string `json:"name"`
+}
+
+type BitbucketRepository struct {
+	MainBranch BitbucketRef `json:"mainbranch"`
+}
+
+type BitbucketCommit struct {
+	Hash string `json:"hash"`
+	Date string `json:"date"`
+}
+
+type BitbucketRefs struct {
+	Values []BitbucketRef `json:"values"`
+	Next   string         `json:"next"`
+}
+
+type BitbucketServerCommit struct {
+	ID                 string `json:"id"`
+	CommitterTimestamp int64  `json:"committerTimestamp"`
+}
+
+type BitbucketServerRef struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+}
+
+type BitbucketServerRefs struct {
+	Values        []BitbucketServerRef `json:"values"`
+	IsLastPage    bool                 `json:"isLastPage"`
+	NextPageStart int                  `json:"nextPageStart"`
+}
+
+type GiteaRepository struct {
+	DefaultBranch string   `json:"default_branch"`
+	Licenses      []string `json:"licenses"`
+}
+
+type GiteaTag struct {
+	Name string `json:"name"`
+}
@@ -1,5 +1,20 @@
 package models
 
+// Statuses of a report object
+const (
+	StatusOK     = "ok"
+	StatusFailed = "failed"
+)
+
+// Gaps between the installed and the latest version of a dependency, named after the most significant part of the
+// version that differs
+const (
+	GapNone  = "none"
+	GapPatch = "patch"
+	GapMinor = "minor"
+	GapMajor = "major"
+)
+
 // Objects used in construction of report
 type VersionDetails struct {
 	Version string `json:"version,omitempty"`
@@ -14,6 +29,101 @@ type ReportObject struct {
 	Website   string         `json:"website"`
 	Installed VersionDetails `json:"installed"`
 	Latest    VersionDetails `json:"latest"`
+	// LatestPrerelease is the latest prerelease tag, when it is newer than the latest version
+	LatestPrerelease string `json:"latestPrerelease,omitempty"`
+	// LatestInMajor is the latest version with the major version of the installed one, which can be upgraded to
+	// without changing import paths
+	LatestInMajor *VersionDetails `json:"latestInMajor,omitempty"`
+	// NewestMajor is the latest version of the newest major version, when it is newer than the installed one
+	NewestMajor *ModuleVersion `json:"newestMajor,omitempty"`
+	// Indirect is set for dependencies that are only required by other dependencies
+	Indirect bool `json:"indirect"`
+	// RequiredBy lists the module versions that require the dependency, when the full build list is reported
+	RequiredBy []string `json:"requiredBy,omitempty"`
+	// Why holds the shortest requirement chains from the main module to the dependency, when requested
+	Why [][]string `json:"why,omitempty"`
+	// UsedBy lists the workspace modules that require the dependency, when reporting on a go.work workspace
+	UsedBy []string `json:"usedBy,omitempty"`
+	// Implicit is set for vendored modules that go.mod does not require
+	Implicit bool `json:"implicit,omitempty"`
+	// Packages lists the packages vendored from the dependency, when reporting on vendor/modules.txt
+	Packages []string `json:"packages,omitempty"`
+	// Sum is the h1: hash of the module contents, when known
+	Sum string `json:"sum,omitempty"`
+	// GoModSum is the h1: hash of the go.mod file of the dependency, when known
+	GoModSum string `json:"goModSum,omitempty"`
+	// SumDB is the result of verifying the hashes against the checksum database, when requested
+	SumDB string `json:"sumdb,omitempty"`
+	// Warnings point out problems that did not prevent resolving the dependency, such as a missing go.sum entry
+	Warnings []string `json:"warnings,omitempty"`
+	// Drift measures how far the installed version is behind the latest one, for resolved dependencies
+	Drift *Drift `json:"drift,omitempty"`
+	// Status is StatusOK when the dependency was resolved and StatusFailed when it was not
+	Status string `json:"status"`
+	// Errors explains why a failed dependency could not be resolved
+	Errors []string `json:"errors,omitempty"`
+	// Undetermined lists the fields that could not be determined, e.g. when resolving offline
+	Undetermined []string `json:"undetermined,omitempty"`
+	// Replace records the replace directive applied to the dependency. Installed and Latest then describe the
+	// effective module.
+	Replace *Replacement `json:"replace,omitempty"`
+}
+
+// ModuleVersion identifies a module version in a report
+type ModuleVersion struct {
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+}
+
+// Replacement describes a module that go.mod replaces with another one
+type Replacement struct {
+	// Original is the module required in go.mod
+	Original ModuleVersion `json:"original"`
+	// Effective is the module used in its place
+	Effective ModuleVersion `json:"effective"`
+	// Local is set when the replacement is a directory on disk, which is not looked up anywhere
+	Local bool `json:"local,omitempty"`
+}
+
+// Drift measures how far the installed version of a dependency is behind the latest version. Measures that cannot be
+// computed are listed in the Undetermined field of the report object.
+type Drift struct {
+	// VersionsBehind counts the releases of the module newer than the installed version
+	VersionsBehind int `json:"versionsBehind"`
+	// Gap is GapMajor, GapMinor or GapPatch for the most significant part of the installed version that is behind
+	// the latest version, and GapNone when it is up to date
+	Gap string `json:"gap,omitempty"`
+	// DaysBehind is the number of whole days between the installed commit and the latest commit
+	DaysBehind int `json:"daysBehind"`
+}
+
+// GapHistogram counts the dependencies of a report by gap between their installed and latest version
+type GapHistogram struct {
+	None  int `json:"none"`
+	Patch int `json:"patch"`
+	Minor int `json:"minor"`
+	Major int `json:"major"`
+	// Unknown counts the dependencies whose gap could not be determined, including the failed ones
+	Unknown int `json:"unknown"`
+}
+
+// Summary counts the dependencies in a report
+type Summary struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Direct    int `json:"direct"`
+	Indirect  int `json:"indirect"`
+	// Drift is the histogram of the gaps between installed and latest versions
+	Drift GapHistogram `json:"drift"`
+}
+
+// Build describes the Go executable a report is about
+type Build struct {
+	// GoVersion is the version of the go toolchain that built the executable, e.g. go1.21.0
+	GoVersion string `json:"goVersion"`
+	// Main is the main module of the executable, whose version is (devel) when built from a working tree
+	Main ModuleVersion `json:"main"`
 }
 
 type Report struct {
@@ -21,5 +131,7 @@ type Report struct {
 	ReportTime   string         `json:"reportTime"`
 	Commit       string         `json:"commit"`
 	CommitTime   string         `json:"commitTime"`
+	Build        *Build         `json:"build,omitempty"`
+	Summary      Summary        `json:"summary"`
 	Dependencies []ReportObject `json:"dependencies"`
 }
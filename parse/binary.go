@@ -0,0 +1,69 @@
+package parse
+
+import (
+	"debug/buildinfo"
+	"runtime/debug"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+)
+
+// Binary is what a Go executable records about how it was built
+type Binary struct {
+	// GoVersion is the version of the go toolchain that built the executable
+	GoVersion string
+	// Main is the main module of the executable
+	Main models.Module
+	// Modules are the modules compiled into the executable
+	Modules []models.Module
+	// Commit and CommitTime come from the vcs.revision and vcs.time build settings, and are empty when the
+	// executable was built outside of a repository or with -buildvcs=false
+	Commit     string
+	CommitTime string
+	// Modified is set when the executable was built from a working tree with uncommitted changes
+	Modified bool
+}
+
+// ReadBinary reads the build info embedded in a Go executable
+func ReadBinary(filepath string) (*Binary, error) {
+	info, err := buildinfo.ReadFile(filepath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read build info from %s", filepath)
+	}
+	return binaryFromBuildInfo(info), nil
+}
+
+func binaryFromBuildInfo(info *debug.BuildInfo) *Binary {
+	binary := Binary{
+		GoVersion: info.GoVersion,
+		Main:      moduleFromBuildInfo(&info.Main),
+	}
+	for _, dep := range info.Deps {
+		binary.Modules = append(binary.Modules, moduleFromBuildInfo(dep))
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			binary.Commit = setting.Value
+		case "vcs.time":
+			binary.CommitTime = setting.Value
+		case "vcs.modified":
+			binary.Modified = setting.Value == "true"
+		}
+	}
+	return &binary
+}
+
+func moduleFromBuildInfo(mod *debug.Module) models.Module {
+	module := models.Module{
+		Path:    mod.Path,
+		Version: mod.Version,
+		Sum:     mod.Sum,
+	}
+	if mod.Replace != nil {
+		replace := moduleFromBuildInfo(mod.Replace)
+		module.Replace = &replace
+	}
+	return module
+}
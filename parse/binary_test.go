@@ -0,0 +1,76 @@
+package parse
+
+import (
+	"os"
+	"runtime/debug"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadBinary(t *testing.T) {
+	// The test binary embeds the build info of this module
+	binary, err := ReadBinary(os.Args[0])
+	if err != nil {
+		t.Fatalf("unable to read test binary, %v", err)
+	}
+	assert.Equal(t, "github.com/1Password/dep-report", binary.Main.Path)
+
+	var paths []string
+	for _, mod := range binary.Modules {
+		paths = append(paths, mod.Path)
+	}
+	assert.Contains(t, paths, "github.com/pkg/errors")
+
+	_, err = ReadBinary("testData/replace.mod")
+	assert.Error(t, err)
+}
+
+func TestBinaryFromBuildInfo(t *testing.T) {
+	info := &debug.BuildInfo{
+		GoVersion: "go1.21.0",
+		Main:      debug.Module{Path: "github.com/1Password/example", Version: "(devel)"},
+		Deps: []*debug.Module{
+			{Path: "github.com/pkg/errors", Version: "v0.9.1", Sum: "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4="},
+			{
+				Path:    "github.com/foo/bar",
+				Version: "v1.0.0",
+				Replace: &debug.Module{Path: "github.com/ourfork/bar", Version: "v1.2.3", Sum: "h1:ourforkbarsum="},
+			},
+			{
+				Path:    "github.com/foo/baz",
+				Version: "v1.1.0",
+				Replace: &debug.Module{Path: "../baz"},
+			},
+		},
+		Settings: []debug.BuildSetting{
+			{Key: "-trimpath", Value: "true"},
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "614d223910a179a466c1767a985424175c39b465"},
+			{Key: "vcs.time", Value: "2020-01-14T19:47:44Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	assert.Equal(t, &Binary{
+		GoVersion: "go1.21.0",
+		Main:      models.Module{Path: "github.com/1Password/example", Version: "(devel)"},
+		Modules: []models.Module{
+			{Path: "github.com/pkg/errors", Version: "v0.9.1", Sum: "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4="},
+			{
+				Path:    "github.com/foo/bar",
+				Version: "v1.0.0",
+				Replace: &models.Module{Path: "github.com/ourfork/bar", Version: "v1.2.3", Sum: "h1:ourforkbarsum="},
+			},
+			{
+				Path:    "github.com/foo/baz",
+				Version: "v1.1.0",
+				Replace: &models.Module{Path: "../baz"},
+			},
+		},
+		Commit:     "614d223910a179a466c1767a985424175c39b465",
+		CommitTime: "2020-01-14T19:47:44Z",
+		Modified:   true,
+	}, binaryFromBuildInfo(info))
+}
@@ -0,0 +1,245 @@
+package parse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ModuleGraph is the module requirement graph, as printed by `go mod graph`
+type ModuleGraph struct {
+	// Main is the path of the main module
+	Main string
+	// Requires maps each module version, written as path@version, to the module versions it requires.
+	// The main module is keyed by its path alone.
+	Requires map[string][]module.Version
+}
+
+// ReadModGraph runs `go mod graph` in the directory of a module and parses its output. Workspace mode is turned off,
+// since a go.work above the module would make every workspace module a main module of the graph.
+func ReadModGraph(dir string) (*ModuleGraph, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "mod", "graph")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to run go mod graph: %s", strings.TrimSpace(stderr.String()))
+	}
+	return ParseModGraph(bytes.NewReader(out))
+}
+
+// ParseModGraph parses the output of `go mod graph`. Each line is an edge from a module to one of its requirements,
+// and the main module is the only one without a version.
+func ParseModGraph(r io.Reader) (*ModuleGraph, error) {
+	graph := ModuleGraph{Requires: map[string][]module.Version{}}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, errors.Errorf("malformed module graph line %d: %q", line, scanner.Text())
+		}
+
+		from, to := splitModuleVersion(fields[0]), splitModuleVersion(fields[1])
+		// Since Go 1.21 the graph also holds the go and toolchain versions, which are not modules
+		if isToolchain(from.Path) || isToolchain(to.Path) {
+			continue
+		}
+		if to.Version == "" {
+			return nil, errors.Errorf("malformed module graph line %d: requirement %s has no version", line, fields[1])
+		}
+		if from.Version == "" {
+			if graph.Main != "" && graph.Main != from.Path {
+				return nil, errors.Errorf("module graph has more than one main module: %s and %s", graph.Main, from.Path)
+			}
+			graph.Main = from.Path
+		}
+
+		graph.Requires[from.String()] = append(graph.Requires[from.String()], to)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "unable to read module graph")
+	}
+
+	if graph.Main == "" {
+		return nil, errors.New("module graph has no main module")
+	}
+	return &graph, nil
+}
+
+// BuildList selects the module versions the go command builds with, using minimal version selection: every module
+// reachable from the main module is selected at the highest version required anywhere in the graph.
+// Each module records the module versions that require it, sorted by path.
+func (g *ModuleGraph) BuildList() []models.Module {
+	selected := map[string]string{}
+	requiredBy := map[string][]string{}
+
+	seen := map[string]bool{g.Main: true}
+	queue := []module.Version{{Path: g.Main}}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+
+		for _, req := range g.Requires[from.String()] {
+			if req.Path == g.Main {
+				continue
+			}
+			if semver.Compare(req.Version, selected[req.Path]) > 0 || selected[req.Path] == "" {
+				selected[req.Path] = req.Version
+			}
+			requiredBy[req.Path] = appendUnique(requiredBy[req.Path], from.String())
+
+			if !seen[req.String()] {
+				seen[req.String()] = true
+				queue = append(queue, req)
+			}
+		}
+	}
+
+	modules := make([]models.Module, 0, len(selected))
+	for path, version := range selected {
+		sort.Strings(requiredBy[path])
+		modules = append(modules, models.Module{
+			Path:       path,
+			Version:    version,
+			RequiredBy: requiredBy[path],
+		})
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Path < modules[j].Path
+	})
+	return modules
+}
+
+// maxWhyChains caps the chains reported for a module, which can multiply quickly in large graphs
+const maxWhyChains = 10
+
+// Why returns the shortest requirement chains from the main module to any version of the module at path.
+// Each chain starts with the main module and lists module versions as path@version. Chains are sorted,
+// at most ten are returned, and none when the main module does not need the module.
+func (g *ModuleGraph) Why(path string) [][]string {
+	dist := map[string]int{g.Main: 0}
+	preds := map[string][]string{}
+	var targets []string
+
+	queue := []string{g.Main}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		// Every chain beyond the first target found is longer than the shortest one
+		if len(targets) > 0 && dist[from] >= dist[targets[0]] {
+			break
+		}
+
+		for _, req := range g.Requires[from] {
+			to := req.String()
+			d, seen := dist[to]
+			switch {
+			case !seen:
+				dist[to] = dist[from] + 1
+				preds[to] = []string{from}
+				queue = append(queue, to)
+				if req.Path == path {
+					targets = append(targets, to)
+				}
+			case d == dist[from]+1:
+				preds[to] = appendUnique(preds[to], from)
+			}
+		}
+	}
+
+	var chains [][]string
+	for _, target := range targets {
+		chains = append(chains, g.chainsTo(target, preds)...)
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return strings.Join(chains[i], " ") < strings.Join(chains[j], " ")
+	})
+	if len(chains) > maxWhyChains {
+		chains = chains[:maxWhyChains]
+	}
+	return chains
+}
+
+// chainsTo walks the predecessors of a node back to the main module
+func (g *ModuleGraph) chainsTo(node string, preds map[string][]string) [][]string {
+	if node == g.Main {
+		return [][]string{{node}}
+	}
+
+	var chains [][]string
+	for _, pred := range preds[node] {
+		for _, chain := range g.chainsTo(pred, preds) {
+			chains = append(chains, append(chain[:len(chain):len(chain)], node))
+			if len(chains) >= maxWhyChains {
+				return chains
+			}
+		}
+	}
+	return chains
+}
+
+// Explain sets the shortest requirement chains of each module from the main module
+func (g *ModuleGraph) Explain(modules []models.Module) {
+	for i := range modules {
+		modules[i].Why = g.Why(modules[i].Path)
+	}
+}
+
+// ParseBuildList returns the full build list of the module whose go.mod is at filepath, selected from its module graph.
+// Modules that go.mod does not require directly are marked indirect, and the replace directives of go.mod are applied.
+func ParseBuildList(filepath string, graph *ModuleGraph) ([]models.Module, error) {
+	formattedMods, err := readModFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	direct := map[string]bool{}
+	for _, mod := range formattedMods.Require {
+		if !mod.Indirect {
+			direct[mod.Mod.Path] = true
+		}
+	}
+
+	modules := graph.BuildList()
+	for i, mod := range modules {
+		modules[i].Indirect = !direct[mod.Path]
+		modules[i].Replace = replacementFor(formattedMods.Replace, mod.Path, mod.Version)
+	}
+	return modules, nil
+}
+
+func splitModuleVersion(s string) module.Version {
+	if i := strings.Index(s, "@"); i >= 0 {
+		return module.Version{Path: s[:i], Version: s[i+1:]}
+	}
+	return module.Version{Path: s}
+}
+
+func isToolchain(path string) bool {
+	return path == "go" || path == "toolchain"
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,167 @@
+package parse
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/module"
+)
+
+func TestParseModGraph(t *testing.T) {
+	tests := []struct {
+		description string
+		graph       string
+		wantMain    string
+		wantError   bool
+	}{
+		{
+			description: "should find the main module",
+			graph:       "example.com/main example.com/dep@v1.0.0\nexample.com/dep@v1.0.0 example.com/other@v0.1.0\n",
+			wantMain:    "example.com/main",
+		},
+		{
+			description: "should fail on malformed lines",
+			graph:       "example.com/main\n",
+			wantError:   true,
+		},
+		{
+			description: "should fail on requirements without a version",
+			graph:       "example.com/main example.com/dep\n",
+			wantError:   true,
+		},
+		{
+			description: "should fail without a main module",
+			graph:       "example.com/dep@v1.0.0 example.com/other@v0.1.0\n",
+			wantError:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			graph, err := ParseModGraph(strings.NewReader(test.graph))
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.wantMain, graph.Main)
+			}
+		})
+	}
+}
+
+func TestReadModGraph(t *testing.T) {
+	// The module is part of a workspace with example.com/b, which requires the same module
+	graph, err := ReadModGraph("testData/graphwork/a")
+	if err != nil {
+		t.Fatalf("unable to read module graph: %v", err)
+	}
+
+	assert.Equal(t, "example.com/a", graph.Main)
+	assert.Equal(t, []module.Version{{Path: "example.com/c", Version: "v0.0.0"}}, graph.Requires["example.com/a"])
+	assert.NotContains(t, graph.Requires, "example.com/b")
+}
+
+func TestParseBuildList(t *testing.T) {
+	file, err := os.Open("testData/graph.txt")
+	if err != nil {
+		t.Fatalf("unable to open module graph: %v", err)
+	}
+	defer file.Close()
+
+	graph, err := ParseModGraph(file)
+	if err != nil {
+		t.Fatalf("unable to parse module graph: %v", err)
+	}
+
+	modules, err := ParseBuildList("testData/graph.mod", graph)
+	if err != nil {
+		t.Fatalf("unable to select build list: %v", err)
+	}
+
+	assert.Equal(t, []models.Module{
+		{
+			Path:       "github.com/foo/bar",
+			Version:    "v1.0.0",
+			RequiredBy: []string{"github.com/1Password/example"},
+		},
+		{
+			Path:       "github.com/foo/old",
+			Version:    "v1.0.0",
+			Indirect:   true,
+			RequiredBy: []string{"github.com/1Password/example", "github.com/pkg/errors@v0.8.1"},
+		},
+		{
+			Path:       "github.com/foo/qux",
+			Version:    "v0.2.0",
+			Indirect:   true,
+			RequiredBy: []string{"github.com/foo/bar@v1.0.0"},
+			Replace:    &models.Module{Path: "github.com/ourfork/qux", Version: "v0.2.1"},
+		},
+		{
+			Path:    "github.com/pkg/errors",
+			Version: "v0.9.1",
+			RequiredBy: []string{
+				"github.com/1Password/example",
+				"github.com/foo/bar@v1.0.0",
+				"github.com/foo/qux@v0.2.0",
+			},
+		},
+	}, modules)
+}
+
+func TestWhy(t *testing.T) {
+	graph, err := ParseModGraph(strings.NewReader(`example.com/main example.com/a@v1.0.0
+example.com/main example.com/b@v1.0.0
+example.com/main example.com/direct@v1.0.0
+example.com/a@v1.0.0 example.com/shared@v1.0.0
+example.com/b@v1.0.0 example.com/shared@v1.1.0
+example.com/shared@v1.0.0 example.com/deep@v0.1.0
+example.com/direct@v1.0.0 example.com/x@v1.0.0
+example.com/x@v1.0.0 example.com/deep@v0.2.0
+`))
+	if err != nil {
+		t.Fatalf("unable to parse module graph: %v", err)
+	}
+
+	tests := []struct {
+		description string
+		path        string
+		wantChains  [][]string
+	}{
+		{
+			description: "should explain direct requirements",
+			path:        "example.com/direct",
+			wantChains:  [][]string{{"example.com/main", "example.com/direct@v1.0.0"}},
+		},
+		{
+			description: "should report every shortest chain",
+			path:        "example.com/shared",
+			wantChains: [][]string{
+				{"example.com/main", "example.com/a@v1.0.0", "example.com/shared@v1.0.0"},
+				{"example.com/main", "example.com/b@v1.0.0", "example.com/shared@v1.1.0"},
+			},
+		},
+		{
+			description: "should only report the shortest chains",
+			path:        "example.com/deep",
+			wantChains: [][]string{
+				{"example.com/main", "example.com/a@v1.0.0", "example.com/shared@v1.0.0", "example.com/deep@v0.1.0"},
+				{"example.com/main", "example.com/direct@v1.0.0", "example.com/x@v1.0.0", "example.com/deep@v0.2.0"},
+			},
+		},
+		{
+			description: "should report no chains for modules that are not needed",
+			path:        "example.com/missing",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.wantChains, graph.Why(test.path))
+		})
+	}
+}
@@ -8,18 +8,14 @@ import (
 	"github.com/1Password/dep-report/models"
 	"github.com/pkg/errors"
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 )
 
 // ParseModules parses the go.mod file and formats the output for further processing
 func ParseModules(filepath string) ([]models.Module, error) {
-	modBytes, err := ioutil.ReadFile(filepath)
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to read go.mod")
-	}
-
-	formattedMods, err := modfile.Parse("go.mod", modBytes, nil)
+	formattedMods, err := readModFile(filepath)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to parse go.mod")
+		return nil, err
 	}
 	var modArray []models.Module
 
@@ -27,40 +23,114 @@ func ParseModules(filepath string) ([]models.Module, error) {
 		var tempMod models.Module
 		tempMod.Path = mod.Mod.Path
 		tempMod.Version = mod.Mod.Version
+		tempMod.Indirect = mod.Indirect
+		tempMod.Replace = replacementFor(formattedMods.Replace, mod.Mod.Path, mod.Mod.Version)
 
 		modArray = append(modArray, tempMod)
 	}
 	return modArray, nil
 }
 
+func readModFile(filepath string) (*modfile.File, error) {
+	modBytes, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read go.mod")
+	}
+
+	formattedMods, err := modfile.Parse("go.mod", modBytes, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse go.mod")
+	}
+	return formattedMods, nil
+}
+
+// replacementFor returns the module replacing a required module version, if any.
+// Like the go command, a replace directive for the exact version wins over one for all versions of the module.
+func replacementFor(replaces []*modfile.Replace, path, version string) *models.Module {
+	var match *modfile.Replace
+	for _, replace := range replaces {
+		if replace.Old.Path != path {
+			continue
+		}
+		if replace.Old.Version == version {
+			match = replace
+			break
+		}
+		if replace.Old.Version == "" {
+			match = replace
+		}
+	}
+
+	if match == nil {
+		return nil
+	}
+	return &models.Module{
+		Path:    match.New.Path,
+		Version: match.New.Version,
+	}
+}
+
 // MapModToDependency takes an array of modules as a param and converts it to an []models.dependency
 func MapModToDependency(modules []models.Module) []models.Dependency {
 	dependencies := make([]models.Dependency, len(modules))
 	for i, mod := range modules {
-		var dependency models.Dependency
-		dependency.Name = cutVersionSuffix(mod.Path)
-
-		// trimming the incompatible flag from the version is necessary to properly
-		// find the version tag in github
-		mod.Version = strings.TrimSuffix(mod.Version, "+incompatible")
-
-		if strings.Contains(mod.Version, "-") {
-			splitVersion := strings.Split(mod.Version, "-")
-			dependency.Revision = splitVersion[len(splitVersion)-1]
-		} else {
-			dependency.Revision = mod.Version
+		dependencies[i] = mapModToDependency(mod)
+	}
+	return dependencies
+}
+
+func mapModToDependency(mod models.Module) models.Dependency {
+	var dependency models.Dependency
+
+	// Directory replacements keep their path as written in go.mod, they have no name, version or revision upstream
+	if mod.Version == "" {
+		dependency.Name = mod.Path
+		dependency.Path = mod.Path
+		return dependency
+	}
+
+	dependency.Name = mod.Path
+	dependency.Path = mod.Path
+	dependency.Root = cutVersionSuffix(mod.Path)
+
+	// trimming the incompatible flag from the version is necessary to properly
+	// find the version tag in github
+	version := strings.TrimSuffix(mod.Version, "+incompatible")
+
+	// Pseudo-versions name a commit rather than a tag: vX.Y.Z-[pre.]0.yyyymmddhhmmss-abcdefabcdef
+	dependency.Revision = version
+	if module.IsPseudoVersion(mod.Version) {
+		dependency.Revision, _ = module.PseudoVersionRev(mod.Version)
+		base, _ := module.PseudoVersionBase(mod.Version)
+		dependency.BaseVersion = strings.TrimSuffix(base, "+incompatible")
+		if commitTime, err := module.PseudoVersionTime(mod.Version); err == nil {
+			dependency.CommitTime = commitTime.UTC().Format("2006-01-02T15:04:05Z")
 		}
-		dependency.Version = mod.Version
+	}
+	dependency.Version = version
+	dependency.Indirect = mod.Indirect
+	dependency.RequiredBy = mod.RequiredBy
+	dependency.Why = mod.Why
+	dependency.UsedBy = mod.UsedBy
+	dependency.Implicit = mod.Implicit
+	dependency.Packages = mod.Packages
+	dependency.Sum = mod.Sum
+	dependency.GoModSum = mod.GoModSum
+	dependency.Warnings = mod.Warnings
 
-		dependencies[i] = dependency
+	if mod.Replace != nil {
+		replace := mapModToDependency(*mod.Replace)
+		dependency.Replace = &replace
 	}
-	return dependencies
+
+	return dependency
 }
 
 var majorVersionSuffixRegex = regexp.MustCompile(`/v[0-9]+$`)
 
 // cutting the major version suffix is necessary in order to properly find the repo
-// because the repo url does not contain the suffix
+// because the repo url does not contain the suffix. gopkg.in paths keep their .vN suffix, which is part of
+// the path the go-get meta tags are served from
 func cutVersionSuffix(path string) string {
 	if majorVersionSuffixRegex.MatchString(path) {
 		splitPath := strings.Split(path, "/")
@@ -14,6 +14,61 @@ func TestParseModules(t *testing.T) {
 	}
 }
 
+func TestParseModulesReplace(t *testing.T) {
+	modules, err := ParseModules("testData/replace.mod")
+	if err != nil {
+		t.Fatalf("unable to parse go.mod, %v", err)
+	}
+
+	assert.Equal(t, []models.Module{
+		{
+			Path:    "github.com/BurntSushi/toml",
+			Version: "v0.3.1",
+		},
+		{
+			Path:    "github.com/foo/bar",
+			Version: "v1.0.0",
+			Replace: &models.Module{Path: "github.com/ourfork/bar", Version: "v1.2.3"},
+		},
+		{
+			Path:    "github.com/foo/baz",
+			Version: "v1.1.0",
+			Replace: &models.Module{Path: "../baz"},
+		},
+		{
+			Path:    "github.com/pkg/errors",
+			Version: "v0.8.1",
+		},
+	}, modules)
+}
+
+func TestParseModulesIndirect(t *testing.T) {
+	modules, err := ParseModules("testData/indirect.mod")
+	if err != nil {
+		t.Fatalf("unable to parse go.mod, %v", err)
+	}
+
+	assert.Equal(t, []models.Module{
+		{
+			Path:    "github.com/BurntSushi/toml",
+			Version: "v0.3.1",
+		},
+		{
+			Path:     "github.com/davecgh/go-spew",
+			Version:  "v1.1.1",
+			Indirect: true,
+		},
+		{
+			Path:    "github.com/pkg/errors",
+			Version: "v0.8.1",
+		},
+	}, modules)
+
+	dependencies := MapModToDependency(modules)
+	assert.False(t, dependencies[0].Indirect)
+	assert.True(t, dependencies[1].Indirect)
+}
+
 func TestMapModToPkg(t *testing.T) {
 	tests := []struct {
 		description string
@@ -36,19 +91,23 @@ func TestMapModToPkg(t *testing.T) {
 				{
 					Source:   "",
 					Name:     "github.com/pkg/errors",
+					Path:     "github.com/pkg/errors",
+					Root:     "github.com/pkg/errors",
 					Revision: "v0.8.1",
 					Version:  "v0.8.1",
 				},
 				{
 					Source:   "",
 					Name:     "github.com/BurntSushi/toml",
+					Path:     "github.com/BurntSushi/toml",
+					Root:     "github.com/BurntSushi/toml",
 					Revision: "v0.3.1",
 					Version:  "v0.3.1",
 				},
 			},
 		},
 		{
-			description: "should handle major version suffixes, removing to properly find repo",
+			description: "should keep major version suffixes in the name, removing them from the repo root",
 			modules: []models.Module{
 				{
 					Path:    "github.com/pkg/errors/v2",
@@ -62,13 +121,17 @@ func TestMapModToPkg(t *testing.T) {
 			wantPkg: []models.Dependency{
 				{
 					Source:   "",
-					Name:     "github.com/pkg/errors",
+					Name:     "github.com/pkg/errors/v2",
+					Path:     "github.com/pkg/errors/v2",
+					Root:     "github.com/pkg/errors",
 					Revision: "v2.8.1",
 					Version:  "v2.8.1",
 				},
 				{
 					Source:   "",
-					Name:     "github.com/BurntSushi/toml",
+					Name:     "github.com/BurntSushi/toml/v33",
+					Path:     "github.com/BurntSushi/toml/v33",
+					Root:     "github.com/BurntSushi/toml",
 					Revision: "v33.3.1",
 					Version:  "v33.3.1",
 				},
@@ -86,6 +149,8 @@ func TestMapModToPkg(t *testing.T) {
 				{
 					Source:   "",
 					Name:     "github.com/pkg/errors",
+					Path:     "github.com/pkg/errors",
+					Root:     "github.com/pkg/errors",
 					Revision: "v2.8.1",
 					Version:  "v2.8.1",
 				},
@@ -105,16 +170,110 @@ func TestMapModToPkg(t *testing.T) {
 			},
 			wantPkg: []models.Dependency{
 				{
-					Source:   "",
-					Name:     "gopkg.in/check.v1",
-					Revision: "20d25e280405",
-					Version:  "v0.0.0-20161208181325-20d25e280405",
+					Source:     "",
+					Name:       "gopkg.in/check.v1",
+					Path:       "gopkg.in/check.v1",
+					Root:       "gopkg.in/check.v1",
+					Revision:   "20d25e280405",
+					Version:    "v0.0.0-20161208181325-20d25e280405",
+					CommitTime: "2016-12-08T18:13:25Z",
 				},
 				{
-					Source:   "",
-					Name:     "github.com/xordataexchange/crypt",
-					Revision: "b2862e3d0a77",
-					Version:  "v0.0.3-0.20170626215501-b2862e3d0a77",
+					Source:      "",
+					Name:        "github.com/xordataexchange/crypt",
+					Path:        "github.com/xordataexchange/crypt",
+					Root:        "github.com/xordataexchange/crypt",
+					Revision:    "b2862e3d0a77",
+					Version:     "v0.0.3-0.20170626215501-b2862e3d0a77",
+					BaseVersion: "v0.0.2",
+					CommitTime:  "2017-06-26T21:55:01Z",
+				},
+			},
+		},
+		{
+			description: "should handle prereleases and pseudo versions on top of them",
+			modules: []models.Module{
+				{
+					Path:    "github.com/foo/bar",
+					Version: "v1.2.0-rc.1",
+				},
+				{
+					Path:    "github.com/foo/baz",
+					Version: "v1.2.0-rc.1.0.20200101120000-b2862e3d0a77",
+				},
+				{
+					Path:    "github.com/foo/qux",
+					Version: "v2.0.1-0.20200101120000-b2862e3d0a77+incompatible",
+				},
+			},
+			wantPkg: []models.Dependency{
+				{
+					Name:     "github.com/foo/bar",
+					Path:     "github.com/foo/bar",
+					Root:     "github.com/foo/bar",
+					Revision: "v1.2.0-rc.1",
+					Version:  "v1.2.0-rc.1",
+				},
+				{
+					Name:        "github.com/foo/baz",
+					Path:        "github.com/foo/baz",
+					Root:        "github.com/foo/baz",
+					Revision:    "b2862e3d0a77",
+					Version:     "v1.2.0-rc.1.0.20200101120000-b2862e3d0a77",
+					BaseVersion: "v1.2.0-rc.1",
+					CommitTime:  "2020-01-01T12:00:00Z",
+				},
+				{
+					Name:        "github.com/foo/qux",
+					Path:        "github.com/foo/qux",
+					Root:        "github.com/foo/qux",
+					Revision:    "b2862e3d0a77",
+					Version:     "v2.0.1-0.20200101120000-b2862e3d0a77",
+					BaseVersion: "v2.0.0",
+					CommitTime:  "2020-01-01T12:00:00Z",
+				},
+			},
+		},
+		{
+			description: "should map replacements",
+			modules: []models.Module{
+				{
+					Path:    "github.com/foo/bar",
+					Version: "v1.0.0",
+					Replace: &models.Module{Path: "github.com/ourfork/bar/v2", Version: "v2.0.0-20200101000000-b2862e3d0a77"},
+				},
+				{
+					Path:    "github.com/foo/baz",
+					Version: "v1.1.0",
+					Replace: &models.Module{Path: "../baz"},
+				},
+			},
+			wantPkg: []models.Dependency{
+				{
+					Name:     "github.com/foo/bar",
+					Path:     "github.com/foo/bar",
+					Root:     "github.com/foo/bar",
+					Revision: "v1.0.0",
+					Version:  "v1.0.0",
+					Replace: &models.Dependency{
+						Name:       "github.com/ourfork/bar/v2",
+						Path:       "github.com/ourfork/bar/v2",
+						Root:       "github.com/ourfork/bar",
+						Revision:   "b2862e3d0a77",
+						Version:    "v2.0.0-20200101000000-b2862e3d0a77",
+						CommitTime: "2020-01-01T00:00:00Z",
+					},
+				},
+				{
+					Name:     "github.com/foo/baz",
+					Path:     "github.com/foo/baz",
+					Root:     "github.com/foo/baz",
+					Revision: "v1.1.0",
+					Version:  "v1.1.0",
+					Replace: &models.Dependency{
+						Name: "../baz",
+						Path: "../baz",
+					},
 				},
 			},
 		},
@@ -127,3 +286,42 @@ func TestMapModToPkg(t *testing.T) {
 		})
 	}
 }
+
+func TestMapModToDependencyBaseVersion(t *testing.T) {
+	tests := []struct {
+		description     string
+		version         string
+		wantBaseVersion string
+	}{
+		{
+			description: "should leave the base version empty for commits that follow no release",
+			version:     "v0.0.0-20161208181325-20d25e280405",
+		},
+		{
+			description:     "should find the prerelease a pseudo-version builds on",
+			version:         "v1.2.0-rc.1.0.20200101120000-b2862e3d0a77",
+			wantBaseVersion: "v1.2.0-rc.1",
+		},
+		{
+			description:     "should find the release a pseudo-version builds on",
+			version:         "v0.0.3-0.20170626215501-b2862e3d0a77",
+			wantBaseVersion: "v0.0.2",
+		},
+		{
+			description:     "should trim the incompatible flag from the base version",
+			version:         "v2.0.1-0.20200101120000-b2862e3d0a77+incompatible",
+			wantBaseVersion: "v2.0.0",
+		},
+		{
+			description: "should leave the base version empty for releases",
+			version:     "v1.2.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got := mapModToDependency(models.Module{Path: "github.com/foo/bar", Version: test.version})
+			assert.Equal(t, test.wantBaseVersion, got.BaseVersion)
+		})
+	}
+}
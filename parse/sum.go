@@ -0,0 +1,117 @@
+package parse
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// ModuleSums are the hashes go.sum records for a module version
+type ModuleSums struct {
+	// Sum is the h1: hash of the module contents
+	Sum string
+	// GoModSum is the h1: hash of the go.mod file of the module
+	GoModSum string
+}
+
+// GoSum holds the entries of go.sum files by module version
+type GoSum map[module.Version]ModuleSums
+
+// ReadGoSum reads and merges go.sum files, such as those of the modules of a workspace and its go.work.sum.
+// Files that do not exist are skipped, like the go command does for modules without dependencies.
+func ReadGoSum(sumPaths ...string) (GoSum, error) {
+	sums := GoSum{}
+	for _, sumPath := range sumPaths {
+		if err := sums.read(sumPath); err != nil {
+			return nil, err
+		}
+	}
+	return sums, nil
+}
+
+func (s GoSum) read(sumPath string) error {
+	file, err := os.Open(sumPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to read %s", sumPath)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return errors.Errorf("malformed %s line %d: %q", sumPath, line, scanner.Text())
+		}
+
+		path, version, hash := fields[0], fields[1], fields[2]
+		if strings.HasSuffix(version, "/go.mod") {
+			mod := module.Version{Path: path, Version: strings.TrimSuffix(version, "/go.mod")}
+			sums := s[mod]
+			sums.GoModSum = hash
+			s[mod] = sums
+		} else {
+			mod := module.Version{Path: path, Version: version}
+			sums := s[mod]
+			sums.Sum = hash
+			s[mod] = sums
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(err, "unable to read %s", sumPath)
+	}
+	return nil
+}
+
+// ReadWorkspaceGoSum reads the go.sum files of every module used by a go.work workspace, and its go.work.sum
+func ReadWorkspaceGoSum(workPath string) (GoSum, error) {
+	workBytes, err := ioutil.ReadFile(workPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read go.work")
+	}
+	work, err := modfile.ParseWork("go.work", workBytes, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse go.work")
+	}
+
+	workDir := filepath.Dir(workPath)
+	sumPaths := []string{workPath + ".sum"}
+	for _, use := range work.Use {
+		sumPaths = append(sumPaths, filepath.Join(workDir, filepath.FromSlash(use.Path), "go.sum"))
+	}
+	return ReadGoSum(sumPaths...)
+}
+
+// Apply sets the hashes of each module from go.sum, warning about modules missing from it. A replaced module gets
+// the hashes of its replacement, which is what go.sum records, and directory replacements need none.
+func (s GoSum) Apply(modules []models.Module) {
+	for i := range modules {
+		hashed := &modules[i]
+		if hashed.Replace != nil {
+			if hashed.Replace.Version == "" {
+				continue
+			}
+			hashed = hashed.Replace
+		}
+
+		sums, ok := s[module.Version{Path: hashed.Path, Version: hashed.Version}]
+		if !ok {
+			modules[i].Warnings = append(modules[i].Warnings, hashed.Path+"@"+hashed.Version+" is missing from go.sum")
+			continue
+		}
+		hashed.Sum = sums.Sum
+		hashed.GoModSum = sums.GoModSum
+	}
+}
@@ -0,0 +1,70 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/module"
+)
+
+func TestReadGoSum(t *testing.T) {
+	sums, err := ReadGoSum("testData/go.sum", "testData/missing.sum")
+	if err != nil {
+		t.Fatalf("unable to read go.sum, %v", err)
+	}
+
+	assert.Equal(t, ModuleSums{
+		Sum:      "h1:WXkYYl6Yr3qBf1K79EBnL4mak0OimBfB0XUf9Vl28OQ=",
+		GoModSum: "h1:xHWCNGjB5oqiDr8zfno3MHue2Ht5sIBksp03qcyfWMU=",
+	}, sums[module.Version{Path: "github.com/BurntSushi/toml", Version: "v0.3.1"}])
+	assert.Len(t, sums, 3)
+
+	_, err = ReadGoSum("testData/replace.mod")
+	assert.Error(t, err)
+}
+
+func TestGoSumApply(t *testing.T) {
+	sums, err := ReadGoSum("testData/go.sum")
+	if err != nil {
+		t.Fatalf("unable to read go.sum, %v", err)
+	}
+	modules, err := ParseModules("testData/replace.mod")
+	if err != nil {
+		t.Fatalf("unable to parse go.mod, %v", err)
+	}
+
+	sums.Apply(modules)
+	assert.Equal(t, []models.Module{
+		{
+			Path:     "github.com/BurntSushi/toml",
+			Version:  "v0.3.1",
+			Sum:      "h1:WXkYYl6Yr3qBf1K79EBnL4mak0OimBfB0XUf9Vl28OQ=",
+			GoModSum: "h1:xHWCNGjB5oqiDr8zfno3MHue2Ht5sIBksp03qcyfWMU=",
+		},
+		{
+			Path:    "github.com/foo/bar",
+			Version: "v1.0.0",
+			Replace: &models.Module{
+				Path:     "github.com/ourfork/bar",
+				Version:  "v1.2.3",
+				Sum:      "h1:ourforkbarsum=",
+				GoModSum: "h1:ourforkbargomodsum=",
+			},
+		},
+		{
+			Path:    "github.com/foo/baz",
+			Version: "v1.1.0",
+			Replace: &models.Module{Path: "../baz"},
+		},
+		{
+			Path:     "github.com/pkg/errors",
+			Version:  "v0.8.1",
+			GoModSum: "h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=",
+		},
+	}, modules)
+
+	modules = []models.Module{{Path: "github.com/foo/missing", Version: "v1.0.0"}}
+	sums.Apply(modules)
+	assert.Equal(t, []string{"github.com/foo/missing@v1.0.0 is missing from go.sum"}, modules[0].Warnings)
+}
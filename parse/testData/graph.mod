@@ -0,0 +1,11 @@
+module github.com/1Password/example
+
+go 1.14
+
+require (
+	github.com/foo/bar v1.0.0
+	github.com/foo/old v1.0.0 // indirect
+	github.com/pkg/errors v0.8.1
+)
+
+replace github.com/foo/qux => github.com/ourfork/qux v0.2.1
@@ -0,0 +1,7 @@
+module example.com/b
+
+go 1.18
+
+require example.com/c v0.0.0
+
+replace example.com/c => ../c
@@ -0,0 +1,9 @@
+module github.com/1Password/dep-report/testdata
+
+go 1.14
+
+require (
+	github.com/BurntSushi/toml v0.3.1
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pkg/errors v0.8.1
+)
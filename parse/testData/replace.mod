@@ -0,0 +1,18 @@
+module github.com/1Password/example
+
+go 1.14
+
+require (
+	github.com/BurntSushi/toml v0.3.1
+	github.com/foo/bar v1.0.0
+	github.com/foo/baz v1.1.0
+	github.com/pkg/errors v0.8.1
+)
+
+replace github.com/foo/bar => github.com/ourfork/bar v1.2.3
+
+replace (
+	github.com/foo/baz => github.com/ourfork/baz v1.0.0
+	github.com/foo/baz v1.1.0 => ../baz
+	github.com/pkg/errors v0.9.1 => github.com/ourfork/errors v0.9.2
+)
@@ -0,0 +1,11 @@
+module github.com/1Password/example/api
+
+go 1.18
+
+require (
+	github.com/1Password/example/shared v0.0.0
+	github.com/foo/bar v1.0.0
+	github.com/pkg/errors v0.9.1
+)
+
+replace github.com/foo/bar => github.com/apifork/bar v1.0.1
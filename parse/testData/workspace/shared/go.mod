@@ -0,0 +1,5 @@
+module github.com/1Password/example/shared
+
+go 1.18
+
+require github.com/pkg/errors v0.8.1 // indirect
@@ -0,0 +1,11 @@
+module github.com/1Password/example/web
+
+go 1.18
+
+require (
+	github.com/BurntSushi/toml v0.3.1 // indirect
+	github.com/foo/bar v1.2.0
+	github.com/foo/baz v1.0.0
+)
+
+replace github.com/foo/baz => ../baz
@@ -0,0 +1,108 @@
+package parse
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+)
+
+// ParseVendor parses a vendor/modules.txt file, which lists the modules vendored by `go mod vendor` and the
+// packages vendored from each. Modules without a `## explicit` marker are not required by go.mod and are marked
+// implicit, and therefore indirect, unless the file predates the markers of Go 1.14. Replacement-only entries,
+// which are not in the build list, are left out.
+func ParseVendor(filepath string) ([]models.Module, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read vendor/modules.txt")
+	}
+	defer file.Close()
+
+	var modArray []models.Module
+	var current *models.Module
+	hasMarkers := false
+	explicit := map[int]bool{}
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "":
+		case strings.HasPrefix(text, "## "):
+			if current == nil {
+				continue
+			}
+			for _, marker := range strings.Split(strings.TrimPrefix(text, "## "), ";") {
+				if strings.TrimSpace(marker) == "explicit" {
+					hasMarkers = true
+					explicit[len(modArray)-1] = true
+				}
+			}
+		case strings.HasPrefix(text, "# "):
+			mod, ok, err := parseVendorModule(strings.TrimPrefix(text, "# "))
+			if err != nil {
+				return nil, errors.Wrapf(err, "malformed vendor/modules.txt line %d", line)
+			}
+			current = nil
+			if ok {
+				modArray = append(modArray, mod)
+				current = &modArray[len(modArray)-1]
+			}
+		case strings.HasPrefix(text, "#"):
+			return nil, errors.Errorf("malformed vendor/modules.txt line %d: %q", line, text)
+		default:
+			if current == nil {
+				return nil, errors.Errorf("malformed vendor/modules.txt line %d: package %s outside of a module", line, text)
+			}
+			current.Packages = append(current.Packages, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "unable to read vendor/modules.txt")
+	}
+
+	if hasMarkers {
+		for i := range modArray {
+			modArray[i].Implicit = !explicit[i]
+			modArray[i].Indirect = modArray[i].Implicit
+		}
+	}
+	return modArray, nil
+}
+
+// parseVendorModule parses a module line, `path version` optionally followed by `=> path [version]`.
+// It returns false for replacement-only entries, which have no version on the left.
+func parseVendorModule(text string) (models.Module, bool, error) {
+	var mod models.Module
+	fields := strings.Fields(text)
+
+	arrow := len(fields)
+	for i, field := range fields {
+		if field == "=>" {
+			arrow = i
+		}
+	}
+	switch arrow {
+	case 1:
+		return mod, false, nil
+	case 2:
+	default:
+		return mod, false, errors.Errorf("unexpected module %q", text)
+	}
+	mod.Path, mod.Version = fields[0], fields[1]
+
+	if arrow < len(fields) {
+		replacement := fields[arrow+1:]
+		switch len(replacement) {
+		case 1:
+			mod.Replace = &models.Module{Path: replacement[0]}
+		case 2:
+			mod.Replace = &models.Module{Path: replacement[0], Version: replacement[1]}
+		default:
+			return mod, false, errors.Errorf("unexpected replacement in %q", text)
+		}
+	}
+	return mod, true, nil
+}
@@ -0,0 +1,101 @@
+package parse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVendor(t *testing.T) {
+	modules, err := ParseVendor("testData/modules.txt")
+	if err != nil {
+		t.Fatalf("unable to parse modules.txt, %v", err)
+	}
+
+	assert.Equal(t, []models.Module{
+		{
+			Path:     "github.com/BurntSushi/toml",
+			Version:  "v0.3.1",
+			Packages: []string{"github.com/BurntSushi/toml"},
+		},
+		{
+			Path:     "github.com/davecgh/go-spew",
+			Version:  "v1.1.0",
+			Indirect: true,
+			Implicit: true,
+			Packages: []string{"github.com/davecgh/go-spew/spew"},
+		},
+		{
+			Path:     "github.com/foo/bar",
+			Version:  "v1.0.0",
+			Packages: []string{"github.com/foo/bar", "github.com/foo/bar/internal/util"},
+			Replace:  &models.Module{Path: "github.com/ourfork/bar", Version: "v1.2.3"},
+		},
+		{
+			Path:     "github.com/foo/baz",
+			Version:  "v1.1.0",
+			Packages: []string{"github.com/foo/baz"},
+			Replace:  &models.Module{Path: "../baz"},
+		},
+	}, modules)
+}
+
+func TestParseVendorFormats(t *testing.T) {
+	tests := []struct {
+		description string
+		modulesTxt  string
+		wantModules []models.Module
+		wantError   bool
+	}{
+		{
+			description: "should not mark modules implicit before Go 1.14",
+			modulesTxt:  "# github.com/pkg/errors v0.8.1\ngithub.com/pkg/errors\n",
+			wantModules: []models.Module{{Path: "github.com/pkg/errors", Version: "v0.8.1", Packages: []string{"github.com/pkg/errors"}}},
+		},
+		{
+			description: "should fail on packages outside of a module",
+			modulesTxt:  "github.com/pkg/errors\n",
+			wantError:   true,
+		},
+		{
+			description: "should fail on malformed modules",
+			modulesTxt:  "# github.com/pkg/errors v0.8.1 extra\n",
+			wantError:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "dep-report-vendor")
+			if err != nil {
+				t.Fatalf("unable to create vendor dir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "modules.txt")
+			if err := ioutil.WriteFile(path, []byte(test.modulesTxt), 0644); err != nil {
+				t.Fatalf("unable to write modules.txt: %v", err)
+			}
+
+			modules, err := ParseVendor(path)
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.wantModules, modules)
+			}
+		})
+	}
+}
+
+func TestParseVendorRepo(t *testing.T) {
+	modules, err := ParseVendor("../vendor/modules.txt")
+	if err != nil {
+		t.Fatalf("unable to parse modules.txt, %v", err)
+	}
+	assert.NotEmpty(t, modules)
+}
@@ -0,0 +1,83 @@
+package parse
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FindModules walks a directory tree and returns the directories holding a go.mod file, relative to root and sorted.
+// Like the go command, it skips vendor and testdata directories and those starting with . or _. Directories matching
+// one of the skip globs are skipped too: globs containing a slash are matched against the slash-separated path
+// relative to root, others against the directory name.
+func FindModules(root string, skip []string) ([]string, error) {
+	for _, glob := range skip {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid skip glob %q", glob)
+		}
+	}
+
+	var dirs []string
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." && skipDir(rel, skip) {
+			return filepath.SkipDir
+		}
+
+		if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+			dirs = append(dirs, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to walk %s", root)
+	}
+
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// ModulePath reads the module path declared in a go.mod file
+func ModulePath(filepath string) (string, error) {
+	formattedMods, err := readModFile(filepath)
+	if err != nil {
+		return "", err
+	}
+	if formattedMods.Module == nil {
+		return "", errors.Errorf("%s has no module directive", filepath)
+	}
+	return formattedMods.Module.Mod.Path, nil
+}
+
+func skipDir(rel string, skip []string) bool {
+	name := path.Base(rel)
+	if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return true
+	}
+
+	for _, glob := range skip {
+		target := name
+		if strings.Contains(glob, "/") {
+			target = rel
+		}
+		if matched, _ := path.Match(glob, target); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package parse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindModules(t *testing.T) {
+	root, err := ioutil.TempDir("", "dep-report-walk")
+	if err != nil {
+		t.Fatalf("unable to create tree: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	for _, dir := range []string{
+		".",
+		"services/api",
+		"services/web",
+		"services/web/vendor/github.com/foo/bar",
+		"services/web/testdata/fixture",
+		"tools/generator",
+		"examples/hello",
+		".github/actions",
+		"_attic/old",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("unable to create tree: %v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(root, dir, "go.mod"), []byte("module example.com/"+dir+"\n"), 0644); err != nil {
+			t.Fatalf("unable to create tree: %v", err)
+		}
+	}
+
+	tests := []struct {
+		description string
+		skip        []string
+		wantDirs    []string
+		wantError   bool
+	}{
+		{
+			description: "should find every module outside vendor, testdata and hidden directories",
+			wantDirs:    []string{".", "examples/hello", "services/api", "services/web", "tools/generator"},
+		},
+		{
+			description: "should skip directories matching a name glob",
+			skip:        []string{"examples"},
+			wantDirs:    []string{".", "services/api", "services/web", "tools/generator"},
+		},
+		{
+			description: "should skip directories matching a path glob",
+			skip:        []string{"services/*", "tools/gen*"},
+			wantDirs:    []string{".", "examples/hello"},
+		},
+		{
+			description: "should fail on invalid globs",
+			skip:        []string{"["},
+			wantError:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			dirs, err := FindModules(root, test.skip)
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.wantDirs, dirs)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package parse
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// FindWorkspace finds the go.work file for a directory the way the go command does: GOWORK names the file,
+// or disables workspaces when set to off, and otherwise the directory and its parents are searched
+func FindWorkspace(dir string) (string, bool) {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return "", false
+	case "":
+	default:
+		return gowork, true
+	}
+
+	for {
+		path := filepath.Join(dir, "go.work")
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// ParseWorkspace parses a go.work file and the go.mod of every module it uses, merging their requirements into one
+// set. A module required by several workspace modules is reported once at the highest version, as minimal version
+// selection would pick, and records the workspace modules that use it. Requirements on other workspace modules are
+// left out, and replace directives in go.work take precedence over those in the go.mod files.
+func ParseWorkspace(workPath string) ([]models.Module, error) {
+	workBytes, err := ioutil.ReadFile(workPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read go.work")
+	}
+	work, err := modfile.ParseWork("go.work", workBytes, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse go.work")
+	}
+
+	workDir := filepath.Dir(workPath)
+	var useDirs []string
+	var modFiles []*modfile.File
+	workspaceModules := map[string]bool{}
+	for _, use := range work.Use {
+		modFile, err := readModFile(filepath.Join(workDir, filepath.FromSlash(use.Path), "go.mod"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read workspace module %s", use.Path)
+		}
+		if modFile.Module == nil {
+			return nil, errors.Errorf("workspace module %s has no module directive", use.Path)
+		}
+		useDirs = append(useDirs, use.Path)
+		modFiles = append(modFiles, modFile)
+		workspaceModules[modFile.Module.Mod.Path] = true
+	}
+
+	var modArray []models.Module
+	index := map[string]int{}
+	for _, modFile := range modFiles {
+		for _, mod := range modFile.Require {
+			if workspaceModules[mod.Mod.Path] {
+				continue
+			}
+
+			j, seen := index[mod.Mod.Path]
+			if !seen {
+				j = len(modArray)
+				index[mod.Mod.Path] = j
+				modArray = append(modArray, models.Module{Path: mod.Mod.Path, Version: mod.Mod.Version, Indirect: true})
+			}
+			tempMod := &modArray[j]
+
+			if semver.Compare(mod.Mod.Version, tempMod.Version) > 0 {
+				tempMod.Version = mod.Mod.Version
+			}
+			tempMod.Indirect = tempMod.Indirect && mod.Indirect
+			tempMod.UsedBy = appendUnique(tempMod.UsedBy, modFile.Module.Mod.Path)
+		}
+	}
+
+	for i := range modArray {
+		modArray[i].Replace = workspaceReplacementFor(work, useDirs, modFiles, modArray[i].Path, modArray[i].Version)
+	}
+	return modArray, nil
+}
+
+// workspaceReplacementFor applies the replace directives of go.work, falling back to those of the workspace modules.
+// Directory replacements in a go.mod are relative to its module, so they are rewritten relative to the workspace.
+func workspaceReplacementFor(work *modfile.WorkFile, useDirs []string, modFiles []*modfile.File, modPath, version string) *models.Module {
+	if replace := replacementFor(work.Replace, modPath, version); replace != nil {
+		return replace
+	}
+
+	for i, modFile := range modFiles {
+		replace := replacementFor(modFile.Replace, modPath, version)
+		if replace == nil {
+			continue
+		}
+		if replace.Version == "" && !filepath.IsAbs(replace.Path) {
+			replace.Path = path.Join(useDirs[i], replace.Path)
+			if replace.Path != ".." && !strings.HasPrefix(replace.Path, "../") {
+				replace.Path = "./" + replace.Path
+			}
+		}
+		return replace
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package parse
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWorkspace(t *testing.T) {
+	modules, err := ParseWorkspace("testData/workspace/go.work")
+	if err != nil {
+		t.Fatalf("unable to parse go.work, %v", err)
+	}
+
+	assert.Equal(t, []models.Module{
+		{
+			Path:    "github.com/foo/bar",
+			Version: "v1.2.0",
+			UsedBy:  []string{"github.com/1Password/example/api", "github.com/1Password/example/web"},
+			Replace: &models.Module{Path: "github.com/ourfork/bar", Version: "v1.2.1"},
+		},
+		{
+			Path:    "github.com/pkg/errors",
+			Version: "v0.9.1",
+			UsedBy:  []string{"github.com/1Password/example/api", "github.com/1Password/example/shared"},
+		},
+		{
+			Path:     "github.com/BurntSushi/toml",
+			Version:  "v0.3.1",
+			Indirect: true,
+			UsedBy:   []string{"github.com/1Password/example/web"},
+		},
+		{
+			Path:    "github.com/foo/baz",
+			Version: "v1.0.0",
+			UsedBy:  []string{"github.com/1Password/example/web"},
+			Replace: &models.Module{Path: "./baz"},
+		},
+	}, modules)
+}
+
+func TestFindWorkspace(t *testing.T) {
+	workspace, err := filepath.Abs("testData/workspace")
+	if err != nil {
+		t.Fatalf("unable to find test workspace: %v", err)
+	}
+
+	tests := []struct {
+		description string
+		dir         string
+		gowork      string
+		wantPath    string
+		wantFound   bool
+	}{
+		{
+			description: "should find go.work in a parent directory",
+			dir:         filepath.Join(workspace, "api"),
+			wantPath:    filepath.Join(workspace, "go.work"),
+			wantFound:   true,
+		},
+		{
+			description: "should use GOWORK",
+			dir:         filepath.Join(workspace, "api"),
+			gowork:      "/elsewhere/go.work",
+			wantPath:    "/elsewhere/go.work",
+			wantFound:   true,
+		},
+		{
+			description: "should not use a workspace when GOWORK is off",
+			dir:         filepath.Join(workspace, "api"),
+			gowork:      "off",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer os.Setenv("GOWORK", os.Getenv("GOWORK"))
+			os.Setenv("GOWORK", test.gowork)
+
+			path, found := FindWorkspace(test.dir)
+			assert.Equal(t, test.wantFound, found)
+			assert.Equal(t, test.wantPath, path)
+		})
+	}
+}
+
+func TestReadWorkspaceGoSum(t *testing.T) {
+	sums, err := ReadWorkspaceGoSum("testData/workspace/go.work")
+	if err != nil {
+		t.Fatalf("unable to read go.sum files, %v", err)
+	}
+
+	assert.Equal(t, GoSum{
+		{Path: "github.com/pkg/errors", Version: "v0.9.1"}:      {Sum: "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4="},
+		{Path: "github.com/BurntSushi/toml", Version: "v0.3.1"}: {Sum: "h1:WXkYYl6Yr3qBf1K79EBnL4mak0OimBfB0XUf9Vl28OQ="},
+	}, sums)
+}
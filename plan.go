@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/1Password/dep-report/report"
+	"github.com/pkg/errors"
+)
+
+// runPlan implements `dep-report plan [report.json]`, printing the go get commands upgrading the dependencies of a
+// report grouped by patch upgrades, minor upgrades and major migrations. The report is read from stdin without
+// an argument. The combined document of -recursive gets one plan per module.
+func runPlan(args []string) {
+	flags := flag.NewFlagSet("plan", flag.ExitOnError)
+	goMod := flags.String("go-mod", "go.mod", "go.mod file the patch and minor upgrades are applied to with -write-go-mod")
+	writeGoMod := flags.String("write-go-mod", "", "write the go.mod with the patch and minor upgrades applied to this file for review")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: dep-report plan [flags] [report.json]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	rawReports, err := readReports(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("unable to read report: %v", err)
+	}
+
+	// A single report is keyed by the empty module path
+	if rawReport, ok := rawReports[""]; ok {
+		plan := report.BuildPlan(rawReport)
+		if *writeGoMod != "" {
+			if err := writePlannedGoMod(*goMod, *writeGoMod, plan); err != nil {
+				log.Fatalf("unable to write go.mod: %v", err)
+			}
+		}
+		report.FormatPlan(os.Stdout, plan)
+		return
+	}
+
+	if *writeGoMod != "" {
+		log.Fatal("-write-go-mod needs the report of a single module, write them with -recursive -output-dir")
+	}
+	formatPlans(os.Stdout, rawReports)
+}
+
+// readReports reads a report generated by dep-report from path, or from stdin when path is empty or -. A single
+// report is returned under the empty module path, the combined document printed by -recursive under the module path
+// of each report.
+func readReports(path string) (map[string]models.Report, error) {
+	var reportBytes []byte
+	var err error
+	if path == "" || path == "-" {
+		reportBytes, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		reportBytes, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(reportBytes, &fields); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal report")
+	}
+	if _, ok := fields["dependencies"]; ok {
+		var rawReport models.Report
+		if err := json.Unmarshal(reportBytes, &rawReport); err != nil {
+			return nil, errors.Wrap(err, "unable to unmarshal report")
+		}
+		return map[string]models.Report{"": rawReport}, nil
+	}
+
+	var rawReports map[string]models.Report
+	if err := json.Unmarshal(reportBytes, &rawReports); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal reports")
+	}
+	if len(rawReports) == 0 {
+		return nil, errors.New("report holds no dependencies")
+	}
+	return rawReports, nil
+}
+
+// formatPlans writes the plan of every report of a combined document, in the order of their module paths
+func formatPlans(w io.Writer, rawReports map[string]models.Report) {
+	modulePaths := make([]string, 0, len(rawReports))
+	for modulePath := range rawReports {
+		modulePaths = append(modulePaths, modulePath)
+	}
+	sort.Strings(modulePaths)
+
+	for i, modulePath := range modulePaths {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "# Module %s\n\n", modulePath)
+		report.FormatPlan(w, report.BuildPlan(rawReports[modulePath]))
+	}
+}
+
+// writePlannedGoMod writes the go.mod at goModPath with the plan applied to outPath, refusing to overwrite the
+// go.mod itself
+func writePlannedGoMod(goModPath, outPath string, plan report.Plan) error {
+	goModAbs, err := filepath.Abs(goModPath)
+	if err != nil {
+		return err
+	}
+	outAbs, err := filepath.Abs(outPath)
+	if err != nil {
+		return err
+	}
+	if goModAbs == outAbs {
+		return errors.Errorf("%s would overwrite the go.mod it is rewritten from", outPath)
+	}
+
+	modBytes, err := ioutil.ReadFile(goModPath)
+	if err != nil {
+		return err
+	}
+	rewritten, err := report.RewriteGoMod(modBytes, plan)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(outPath, rewritten, 0644)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadReports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dep-report-plan")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		description string
+		report      string
+		wantReports map[string]models.Report
+		wantError   bool
+	}{
+		{
+			description: "should read a single report under the empty module path",
+			report:      `{"product":"app","dependencies":[{"name":"github.com/foo/bar"}]}`,
+			wantReports: map[string]models.Report{
+				"": {Product: "app", Dependencies: []models.ReportObject{{Name: "github.com/foo/bar"}}},
+			},
+		},
+		{
+			description: "should read the combined reports of -recursive under their module paths",
+			report: `{
+				"example.com/a": {"product":"app","dependencies":[{"name":"github.com/foo/bar"}]},
+				"example.com/a/tools": {"product":"app","dependencies":[]}
+			}`,
+			wantReports: map[string]models.Report{
+				"example.com/a":       {Product: "app", Dependencies: []models.ReportObject{{Name: "github.com/foo/bar"}}},
+				"example.com/a/tools": {Product: "app", Dependencies: []models.ReportObject{}},
+			},
+		},
+		{
+			description: "should reject an empty document",
+			report:      `{}`,
+			wantError:   true,
+		},
+		{
+			description: "should reject a document that is no report",
+			report:      `[]`,
+			wantError:   true,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i))+".json")
+			if err := ioutil.WriteFile(path, []byte(test.report), 0644); err != nil {
+				t.Fatalf("unable to write report: %v", err)
+			}
+
+			got, err := readReports(path)
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.wantReports, got)
+			}
+		})
+	}
+}
+
+func TestFormatPlans(t *testing.T) {
+	rawReports := map[string]models.Report{
+		"example.com/b": {},
+		"example.com/a": {
+			Dependencies: []models.ReportObject{
+				{
+					Name:      "github.com/foo/bar",
+					Installed: models.VersionDetails{Version: "v1.0.0"},
+					Latest:    models.VersionDetails{Version: "v1.0.1"},
+					Status:    models.StatusOK,
+				},
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	formatPlans(&out, rawReports)
+	assert.Equal(t, `# Module example.com/a
+
+# Patch upgrades, should be safe to apply
+go get github.com/foo/bar@v1.0.1 # from v1.0.0
+
+# Minor upgrades, may add features or deprecate APIs
+# (none)
+
+# Major migrations, require adapting to breaking changes
+# (none)
+
+# Module example.com/b
+
+# Patch upgrades, should be safe to apply
+# (none)
+
+# Minor upgrades, may add features or deprecate APIs
+# (none)
+
+# Major migrations, require adapting to breaking changes
+# (none)
+`, out.String())
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/1Password/dep-report/parse"
+	"github.com/1Password/dep-report/report"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/module"
+)
+
+type recursiveOptions struct {
+	// skip holds globs of directories to skip besides vendor and testdata
+	skip []string
+	// outputDir receives one report file per module, the combined document is printed when empty
+	outputDir string
+	all       bool
+	why       bool
+}
+
+// runRecursive reports on every module below the working directory. All reports are built by the same generator,
+// so a dependency shared by several modules is only looked up once.
+func runRecursive(ctx context.Context, g *report.Generator, productName string, options recursiveOptions) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	dirs, err := parse.FindModules(wd, options.skip)
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return errors.Errorf("no go.mod found below %s", wd)
+	}
+
+	reports := map[string]models.Report{}
+	var failed []string
+	for _, dir := range dirs {
+		modulePath, err := parse.ModulePath(filepath.Join(wd, dir, goModFilePath))
+		if err != nil {
+			return err
+		}
+		if _, ok := reports[modulePath]; ok {
+			return errors.Errorf("module %s is declared in more than one go.mod", modulePath)
+		}
+
+		dependencies, err := getModuleDependencies(filepath.Join(wd, dir), options.all, options.why)
+		if err != nil {
+			return errors.Wrapf(err, "unable to parse dependencies of %s", modulePath)
+		}
+
+		log.Printf("reporting on %s in %s", modulePath, dir)
+		rawReport, err := g.BuildReportContext(ctx, productName, dependencies)
+		if err != nil && errors.Cause(err) != report.ErrDependenciesFailed {
+			return errors.Wrapf(err, "unable to generate report for %s", modulePath)
+		}
+		if err != nil {
+			failed = append(failed, modulePath)
+		}
+		reports[modulePath] = *rawReport
+	}
+
+	if options.outputDir != "" {
+		err = writeReports(options.outputDir, reports)
+	} else {
+		err = printReports(reports)
+	}
+	if err != nil {
+		return err
+	}
+
+	// In fail-at-end mode the reports are still written before exiting with an error
+	if len(failed) > 0 {
+		return errors.Wrapf(report.ErrDependenciesFailed, "in %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func printReports(reports map[string]models.Report) error {
+	prettyReports, err := report.FormatReports(reports)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(prettyReports))
+	return nil
+}
+
+// writeReports writes each report to a file named after its module path, escaped like in the module cache and with
+// its slashes percent-encoded so that no two module paths share a file name
+func writeReports(dir string, reports map[string]models.Report) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "unable to create %s", dir)
+	}
+
+	for modulePath, rawReport := range reports {
+		prettyReport, err := report.FormatReport(rawReport)
+		if err != nil {
+			return err
+		}
+		escapedPath, err := module.EscapePath(modulePath)
+		if err != nil {
+			return errors.Wrapf(err, "unable to escape module path %s", modulePath)
+		}
+		path := filepath.Join(dir, url.PathEscape(escapedPath)+".json")
+		if err := ioutil.WriteFile(path, append(prettyReport, '\n'), 0644); err != nil {
+			return errors.Wrapf(err, "unable to write report for %s", modulePath)
+		}
+	}
+	return nil
+}
+
+// splitList splits a comma separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
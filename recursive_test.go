@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteReports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dep-report-reports")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Module paths that only differ in where slashes and underscores are, or in case, must not share a file
+	reports := map[string]models.Report{
+		"example.com/a/b_c":   {Product: "a/b_c"},
+		"example.com/a_b/c":   {Product: "a_b/c"},
+		"example.com/Foo/bar": {Product: "Foo/bar"},
+		"example.com/foo/bar": {Product: "foo/bar"},
+	}
+	if err := writeReports(filepath.Join(dir, "reports"), reports); err != nil {
+		t.Fatalf("error returned from writeReports, err: %v", err)
+	}
+
+	wantFiles := map[string]string{
+		"example.com%2Fa%2Fb_c.json":      "a/b_c",
+		"example.com%2Fa_b%2Fc.json":      "a_b/c",
+		"example.com%2F%21foo%2Fbar.json": "Foo/bar",
+		"example.com%2Ffoo%2Fbar.json":    "foo/bar",
+	}
+	files, err := ioutil.ReadDir(filepath.Join(dir, "reports"))
+	if err != nil {
+		t.Fatalf("unable to list reports: %v", err)
+	}
+	assert.Len(t, files, len(wantFiles))
+	for name, wantProduct := range wantFiles {
+		reportBytes, err := ioutil.ReadFile(filepath.Join(dir, "reports", name))
+		if !assert.NoError(t, err) {
+			continue
+		}
+		var rawReport models.Report
+		if assert.NoError(t, json.Unmarshal(reportBytes, &rawReport)) {
+			assert.Equal(t, wantProduct, rawReport.Product)
+		}
+	}
+}
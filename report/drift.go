@@ -0,0 +1,114 @@
+package report
+
+import (
+	"time"
+
+	"github.com/1Password/dep-report/models"
+	"golang.org/x/mod/semver"
+)
+
+// Drift measures reported as undetermined when the versions or times they are computed from are unknown
+const (
+	UndeterminedVersionsBehind = "drift.versionsBehind"
+	UndeterminedGap            = "drift.gap"
+	UndeterminedDaysBehind     = "drift.daysBehind"
+)
+
+// measureDrift computes how far the installed version of a resolved dependency is behind the latest version.
+// Versions behind are counted among the known releases of the module. Dependencies without any version or time to
+// measure, such as those of unknown source, get no drift at all.
+func measureDrift(reportObject *models.ReportObject, releases []string) {
+	var drift models.Drift
+	var undetermined []string
+	installed := reportObject.Installed.Version
+	latest := reportObject.Latest.Version
+	if latest == "" && reportObject.LatestInMajor != nil {
+		latest = reportObject.LatestInMajor.Version
+	}
+
+	if len(releases) > 0 && semver.IsValid(installed) {
+		for _, release := range releases {
+			if semver.Compare(release, installed) > 0 {
+				drift.VersionsBehind++
+			}
+		}
+	} else {
+		undetermined = append(undetermined, UndeterminedVersionsBehind)
+	}
+
+	if gap, ok := versionGap(installed, latest); ok {
+		drift.Gap = gap
+	} else {
+		undetermined = append(undetermined, UndeterminedGap)
+	}
+
+	if days, ok := daysBetween(reportObject.Installed.Time, reportObject.Latest.Time); ok {
+		drift.DaysBehind = days
+	} else {
+		undetermined = append(undetermined, UndeterminedDaysBehind)
+	}
+
+	if len(undetermined) == 3 {
+		return
+	}
+	reportObject.Drift = &drift
+	reportObject.Undetermined = append(reportObject.Undetermined, undetermined...)
+}
+
+// versionGap classifies how far installed is behind latest by the most significant part of the version that differs.
+// Pseudo-versions compare like the releases they build on, so a commit after the latest release is up to date.
+func versionGap(installed, latest string) (string, bool) {
+	if !semver.IsValid(installed) || !semver.IsValid(latest) {
+		return "", false
+	}
+
+	switch {
+	case semver.Compare(installed, latest) >= 0:
+		return models.GapNone, true
+	case semver.Major(installed) != semver.Major(latest):
+		return models.GapMajor, true
+	case semver.MajorMinor(installed) != semver.MajorMinor(latest):
+		return models.GapMinor, true
+	default:
+		return models.GapPatch, true
+	}
+}
+
+// daysBetween returns the whole days from the installed to the latest commit time, and zero when the installed
+// commit is the newer one
+func daysBetween(installed, latest string) (int, bool) {
+	installedTime, err := time.Parse(time.RFC3339, installed)
+	if err != nil {
+		return 0, false
+	}
+	latestTime, err := time.Parse(time.RFC3339, latest)
+	if err != nil {
+		return 0, false
+	}
+
+	if latestTime.Before(installedTime) {
+		return 0, true
+	}
+	return int(latestTime.Sub(installedTime).Hours() / 24), true
+}
+
+// countGap adds the gap of a dependency to the histogram of a report summary
+func countGap(histogram *models.GapHistogram, drift *models.Drift) {
+	if drift == nil {
+		histogram.Unknown++
+		return
+	}
+
+	switch drift.Gap {
+	case models.GapNone:
+		histogram.None++
+	case models.GapPatch:
+		histogram.Patch++
+	case models.GapMinor:
+		histogram.Minor++
+	case models.GapMajor:
+		histogram.Major++
+	default:
+		histogram.Unknown++
+	}
+}
@@ -0,0 +1,103 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasureDrift(t *testing.T) {
+	tests := []struct {
+		description      string
+		reportObject     models.ReportObject
+		releases         []string
+		wantDrift        *models.Drift
+		wantUndetermined []string
+	}{
+		{
+			description: "should measure a dependency a minor version behind",
+			reportObject: models.ReportObject{
+				Installed: models.VersionDetails{Version: "v1.2.3", Time: "2020-01-01T00:00:00Z"},
+				Latest:    models.VersionDetails{Version: "v1.4.0", Time: "2020-03-01T12:00:00Z"},
+			},
+			releases:  []string{"v1.2.2", "v1.2.3", "v1.2.4", "v1.3.0", "v1.4.0"},
+			wantDrift: &models.Drift{VersionsBehind: 3, Gap: models.GapMinor, DaysBehind: 60},
+		},
+		{
+			description: "should classify major and patch gaps",
+			reportObject: models.ReportObject{
+				Installed: models.VersionDetails{Version: "v1.2.3", Time: "2020-01-01T00:00:00Z"},
+				Latest:    models.VersionDetails{Version: "v2.0.0+incompatible", Time: "2020-01-02T00:00:00Z"},
+			},
+			releases:  []string{"v1.2.3", "v2.0.0+incompatible"},
+			wantDrift: &models.Drift{VersionsBehind: 1, Gap: models.GapMajor, DaysBehind: 1},
+		},
+		{
+			description: "should report dependencies on the latest version as up to date",
+			reportObject: models.ReportObject{
+				Installed: models.VersionDetails{Version: "v1.4.0", Time: "2020-03-01T00:00:00Z"},
+				Latest:    models.VersionDetails{Version: "v1.4.0", Time: "2020-03-01T00:00:00Z"},
+			},
+			releases:  []string{"v1.3.0", "v1.4.0"},
+			wantDrift: &models.Drift{Gap: models.GapNone},
+		},
+		{
+			description: "should report commits after the latest release as up to date",
+			reportObject: models.ReportObject{
+				Installed: models.VersionDetails{Version: "v0.0.3-0.20170626215501-b2862e3d0a77", Time: "2017-06-26T21:55:01Z"},
+				Latest:    models.VersionDetails{Version: "v0.0.2", Time: "2017-06-26T21:55:01Z"},
+			},
+			releases:  []string{"v0.0.1", "v0.0.2"},
+			wantDrift: &models.Drift{Gap: models.GapNone},
+		},
+		{
+			description: "should classify patch gaps and fall back to the latest version in the major line",
+			reportObject: models.ReportObject{
+				Installed:     models.VersionDetails{Version: "v1.2.3"},
+				LatestInMajor: &models.VersionDetails{Version: "v1.2.5"},
+			},
+			wantDrift:        &models.Drift{Gap: models.GapPatch},
+			wantUndetermined: []string{UndeterminedVersionsBehind, UndeterminedDaysBehind},
+		},
+		{
+			description: "should only measure the days behind without versions",
+			reportObject: models.ReportObject{
+				Installed: models.VersionDetails{Time: "2018-06-28T17:31:08Z"},
+				Latest:    models.VersionDetails{Time: "2020-02-27T12:52:54Z"},
+			},
+			wantDrift:        &models.Drift{DaysBehind: 608},
+			wantUndetermined: []string{UndeterminedVersionsBehind, UndeterminedGap},
+		},
+		{
+			description:  "should leave out the drift when nothing can be measured",
+			reportObject: models.ReportObject{Name: "gopkg.in/fake"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			reportObject := test.reportObject
+			measureDrift(&reportObject, test.releases)
+			assert.Equal(t, test.wantDrift, reportObject.Drift)
+			assert.Equal(t, test.wantUndetermined, reportObject.Undetermined)
+		})
+	}
+}
+
+func TestCountGap(t *testing.T) {
+	var histogram models.GapHistogram
+	for _, drift := range []*models.Drift{
+		{Gap: models.GapNone},
+		{Gap: models.GapMajor},
+		{Gap: models.GapMajor},
+		{Gap: models.GapMinor},
+		{Gap: models.GapPatch},
+		{DaysBehind: 10},
+		nil,
+	} {
+		countGap(&histogram, drift)
+	}
+
+	assert.Equal(t, models.GapHistogram{None: 1, Patch: 1, Minor: 1, Major: 2, Unknown: 2}, histogram)
+}
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// FailureMode controls what BuildReport does with dependencies that cannot be resolved
+type FailureMode int
+
+const (
+	// FailFast aborts the report on the first dependency that cannot be resolved
+	FailFast FailureMode = iota
+	// BestEffort records failed dependencies in the report and returns it without an error
+	BestEffort
+	// FailAtEnd records failed dependencies in the report and returns it with ErrDependenciesFailed
+	FailAtEnd
+)
+
+// ErrDependenciesFailed is returned with the report in FailAtEnd mode when any dependency could not be resolved
+var ErrDependenciesFailed = errors.New("dependencies could not be resolved")
+
+var failureModeNames = map[FailureMode]string{
+	FailFast:   "fail-fast",
+	BestEffort: "best-effort",
+	FailAtEnd:  "fail-at-end",
+}
+
+func (m FailureMode) String() string {
+	if name, ok := failureModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("FailureMode(%d)", int(m))
+}
+
+// ParseFailureMode parses the name of a failure mode: fail-fast, best-effort or fail-at-end
+func ParseFailureMode(name string) (FailureMode, error) {
+	for mode, modeName := range failureModeNames {
+		if modeName == name {
+			return mode, nil
+		}
+	}
+	return FailFast, fmt.Errorf("unknown failure mode %q, expected fail-fast, best-effort or fail-at-end", name)
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/1Password/dep-report/versioncontrol"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildReportFailureModes(t *testing.T) {
+	dependencies := []models.Dependency{
+		{Name: "github.com/pkg/errors", Revision: "v0.8.1", Version: "v0.8.1"},
+		{Name: "example.invalid/pkg", Version: "v1.0.0"},
+	}
+
+	tests := []struct {
+		description string
+		failureMode FailureMode
+		wantReport  bool
+		wantCause   error
+	}{
+		{
+			description: "should abort on the first failure when failing fast",
+			failureMode: FailFast,
+		},
+		{
+			description: "should report failures without an error when best effort",
+			failureMode: BestEffort,
+			wantReport:  true,
+		},
+		{
+			description: "should report failures with an error when failing at the end",
+			failureMode: FailAtEnd,
+			wantReport:  true,
+			wantCause:   ErrDependenciesFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			g := Generator{
+				request: versioncontrol.Client{
+					HttpClient: &http.Client{Transport: offlineTransport{}},
+					Retry:      versioncontrol.Retry{BaseDelay: time.Millisecond},
+				},
+				FailureMode: test.failureMode,
+			}
+
+			gotReport, err := g.BuildReportContext(context.Background(), "dep-report", dependencies)
+			if !test.wantReport {
+				assert.Error(t, err)
+				assert.Nil(t, gotReport)
+				return
+			}
+
+			assert.Equal(t, test.wantCause, errors.Cause(err))
+			if assert.NotNil(t, gotReport) {
+				assert.Equal(t, models.Summary{Total: 2, Succeeded: 1, Failed: 1, Direct: 2, Drift: models.GapHistogram{Unknown: 2}}, gotReport.Summary)
+				assert.Equal(t, models.StatusFailed, gotReport.Dependencies[0].Status)
+				assert.Equal(t, "v0.8.1", gotReport.Dependencies[0].Installed.Version)
+				assert.Len(t, gotReport.Dependencies[0].Errors, 1)
+				assert.Equal(t, models.StatusOK, gotReport.Dependencies[1].Status)
+			}
+		})
+	}
+}
+
+func TestParseFailureMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantMode  FailureMode
+		wantError bool
+	}{
+		{name: "fail-fast", wantMode: FailFast},
+		{name: "best-effort", wantMode: BestEffort},
+		{name: "fail-at-end", wantMode: FailAtEnd},
+		{name: "ignore", wantError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mode, err := ParseFailureMode(test.name)
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantMode, mode)
+			assert.Equal(t, test.name, mode.String())
+		})
+	}
+}
@@ -1,24 +1,76 @@
 package report
 
 import (
+	"github.com/1Password/dep-report/models"
 	"github.com/1Password/dep-report/versioncontrol"
 	"net/http"
 	"time"
 )
 
-//Generator is used to drive the report generation and data retrieval
+// Generator is used to drive the report generation and data retrieval
 type Generator struct {
 	//Client contains details needed to make API calls to github/gerrit/gitlab/etc
 	request versioncontrol.Client
+	//registry chooses the provider used to look up each dependency
+	registry *versioncontrol.Registry
+	//resolved shares resolved dependencies between the reports built by the generator, nil to not share them
+	resolved *resolvedCache
+
+	//Workers is the number of dependencies resolved concurrently, defaulting to 8
+	Workers int
+	//HostConcurrency limits the dependencies resolved concurrently against a single host, unlimited when zero
+	HostConcurrency int
+	//FailureMode chooses whether a dependency that cannot be resolved aborts the report, defaulting to FailFast
+	FailureMode FailureMode
+	//MaxRateLimitWait is the longest a request waits for a rate limit to reset before failing, defaulting to a minute
+	MaxRateLimitWait time.Duration
+	//Cache stores API responses on disk between runs, nil to always use the network
+	Cache *versioncontrol.Cache
+	//Offline resolves dependencies from the module cache only, without any network access
+	Offline bool
+	//Commit is reported instead of the HEAD commit of the working directory, e.g. when reporting on a binary
+	Commit *Commit
+	//Build describes the executable reported on, nil when reporting on a dependency file
+	Build *models.Build
+	//SumDB is the checksum database go.sum hashes are verified against, nil to not verify them
+	SumDB *versioncontrol.SumDB
+	//ExcludePrereleases keeps prerelease tags from being reported as the latest version of a dependency
+	ExcludePrereleases bool
+	//DirectOnly leaves the dependencies go.mod marks as indirect out of the report
+	DirectOnly bool
+}
+
+// Commit identifies the commit a report is for
+type Commit struct {
+	//Hash is the full commit hash
+	Hash string
+	//Time is the commit time in RFC 3339 format
+	Time string
 }
 
-//NewGenerator creates a Generator struct
+// NewGenerator creates a Generator struct. Module proxy settings are read from GOPROXY, GONOPROXY and GOPRIVATE,
+// and the module cache used offline from GOMODCACHE and GOPATH
 func NewGenerator(githubToken string, productName string) *Generator {
 	generator := Generator{
 		request: versioncontrol.Client{
 			HttpClient: &http.Client{Timeout: 5 * time.Second},
 			Token:      githubToken,
+			GoProxy:    versioncontrol.GoProxyFromEnv(),
+			Gitlab:     versioncontrol.GitlabFromEnv(),
+			Bitbucket:  versioncontrol.BitbucketFromEnv(),
+			Gitea:      versioncontrol.GiteaFromEnv(),
+			ModCache:   versioncontrol.ModCacheFromEnv(),
 		},
+		registry: versioncontrol.DefaultRegistry(),
+		resolved: &resolvedCache{},
 	}
 	return &generator
 }
+
+// Registry returns the provider registry of the generator, so that providers for other hosts can be registered
+func (g *Generator) Registry() *versioncontrol.Registry {
+	if g.registry == nil {
+		g.registry = versioncontrol.DefaultRegistry()
+	}
+	return g.registry
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/1Password/dep-report/models"
+)
+
+// defaultWorkers is the number of dependencies resolved concurrently when Generator.Workers is not set
+const defaultWorkers = 8
+
+// hostLimiter bounds the number of dependencies resolved concurrently against the same host
+type hostLimiter struct {
+	limit int
+
+	mu    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+// newHostLimiter creates a limiter allowing limit concurrent lookups per host. A limit of zero or less disables it.
+func newHostLimiter(limit int) *hostLimiter {
+	return &hostLimiter{
+		limit: limit,
+		hosts: map[string]chan struct{}{},
+	}
+}
+
+// acquire waits for a slot on the host about to be contacted and returns the function releasing it
+func (l *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	if l == nil || l.limit <= 0 {
+		return func() {}, nil
+	}
+
+	slots := l.slots(strings.ToLower(host))
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *hostLimiter) slots(host string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slots, ok := l.hosts[host]
+	if !ok {
+		slots = make(chan struct{}, l.limit)
+		l.hosts[host] = slots
+	}
+	return slots
+}
+
+// dependencyHost returns the host of the dependency repo, falling back to the first element of its name
+func dependencyHost(dep models.Dependency) string {
+	if u, err := url.Parse(dep.Repo); err == nil && u.Host != "" {
+		return strings.ToLower(u.Host)
+	}
+	return strings.ToLower(strings.SplitN(dep.Name, "/", 2)[0])
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostLimiter(t *testing.T) {
+	tests := []struct {
+		description string
+		limit       int
+		wantMax     int32
+	}{
+		{
+			description: "should bound concurrent lookups on one host",
+			limit:       2,
+			wantMax:     2,
+		},
+		{
+			description: "should not bound lookups without a limit",
+			limit:       0,
+			wantMax:     10,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			limiter := newHostLimiter(test.limit)
+
+			var running, max int32
+			var start, wg sync.WaitGroup
+			start.Add(1)
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					start.Wait()
+					release, err := limiter.acquire(context.Background(), "github.com")
+					if err != nil {
+						t.Errorf("unable to acquire slot: %v", err)
+						return
+					}
+					defer release()
+
+					n := atomic.AddInt32(&running, 1)
+					for {
+						m := atomic.LoadInt32(&max)
+						if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+							break
+						}
+					}
+					time.Sleep(20 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+				}()
+			}
+			start.Done()
+			wg.Wait()
+
+			assert.Equal(t, test.wantMax, max)
+		})
+	}
+}
+
+func TestDependencyHost(t *testing.T) {
+	tests := []struct {
+		description string
+		dependency  models.Dependency
+		wantHost    string
+	}{
+		{
+			description: "should use the repo host",
+			dependency:  models.Dependency{Name: "go.uber.org/zap", Repo: "https://github.com/uber-go/zap"},
+			wantHost:    "github.com",
+		},
+		{
+			description: "should fall back to the package host",
+			dependency:  models.Dependency{Name: "Gopkg.in/fake"},
+			wantHost:    "gopkg.in",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.wantHost, dependencyHost(test.dependency))
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/1Password/dep-report/versioncontrol"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// Upgrade is one step of an upgrade plan, moving a dependency from its installed version to a newer one
+type Upgrade struct {
+	// Path is the module path of the installed dependency
+	Path string
+	From string
+	// NewPath is the module path to upgrade to. It differs from Path for major versions with their own module path,
+	// whose importers must change their import paths.
+	NewPath    string
+	To         string
+	Indirect   bool
+	DaysBehind int
+}
+
+// Command returns the go get command performing the upgrade
+func (u Upgrade) Command() string {
+	return fmt.Sprintf("go get %s@%s", u.NewPath, u.To)
+}
+
+// Plan groups the upgrades available to the dependencies of a report by how much they may break
+type Plan struct {
+	// Patch upgrades stay within the installed minor version and should be safe to apply
+	Patch []Upgrade
+	// Minor upgrades stay within the installed major version
+	Minor []Upgrade
+	// Major migrations move to the newest major version of a dependency
+	Major []Upgrade
+	// Skipped lists the dependencies that cannot be upgraded with go get, with the reason
+	Skipped []string
+}
+
+// BuildPlan creates the upgrade plan for the dependencies of a report. A dependency gets a patch or minor upgrade to
+// the latest version within its major version and, when a newer major version exists, a major migration as well.
+// Failed dependencies and replaced ones, which go get does not upgrade, are skipped.
+func BuildPlan(report models.Report) Plan {
+	var plan Plan
+	for _, reportObject := range report.Dependencies {
+		installed := versioncontrol.IncompatibleVersion(reportObject.Name, reportObject.Installed.Version)
+		switch {
+		case reportObject.Status == models.StatusFailed:
+			plan.Skipped = append(plan.Skipped, fmt.Sprintf("%s: not resolved", reportObject.Name))
+			continue
+		case reportObject.Replace != nil:
+			plan.Skipped = append(plan.Skipped, fmt.Sprintf("%s: replaced by %s", reportObject.Name, reportObject.Replace.Effective.Path))
+			continue
+		case !semver.IsValid(installed):
+			plan.Skipped = append(plan.Skipped, fmt.Sprintf("%s: installed version unknown", reportObject.Name))
+			continue
+		}
+
+		upgrade := Upgrade{
+			Path:     reportObject.Name,
+			From:     installed,
+			NewPath:  reportObject.Name,
+			Indirect: reportObject.Indirect,
+		}
+		if reportObject.Drift != nil {
+			upgrade.DaysBehind = reportObject.Drift.DaysBehind
+		}
+
+		if latest := latestInMajor(reportObject); latest != "" {
+			upgrade.To = latest
+			switch gap, _ := versionGap(installed, latest); gap {
+			case models.GapPatch:
+				plan.Patch = append(plan.Patch, upgrade)
+			case models.GapMinor:
+				plan.Minor = append(plan.Minor, upgrade)
+			}
+		}
+
+		if newest := reportObject.NewestMajor; newest != nil && semver.Compare(newest.Version, installed) > 0 {
+			upgrade.NewPath = newest.Path
+			upgrade.To = newest.Version
+			plan.Major = append(plan.Major, upgrade)
+		}
+	}
+	return plan
+}
+
+// latestInMajor returns the latest version of a dependency that keeps its installed major version, preferring the
+// one looked up under its module path over the latest tag of its repository. Tags are reported without the
+// +incompatible suffix that go get and go.mod need for v2 and later versions of modules without a major version suffix.
+func latestInMajor(reportObject models.ReportObject) string {
+	if reportObject.LatestInMajor != nil && semver.IsValid(reportObject.LatestInMajor.Version) {
+		return versioncontrol.IncompatibleVersion(reportObject.Name, reportObject.LatestInMajor.Version)
+	}
+	latest := reportObject.Latest.Version
+	if semver.IsValid(latest) && semver.Major(latest) == semver.Major(reportObject.Installed.Version) {
+		return versioncontrol.IncompatibleVersion(reportObject.Name, latest)
+	}
+	return ""
+}
+
+// FormatPlan writes the plan as a shell script of go get commands, one section per group of upgrades
+func FormatPlan(w io.Writer, plan Plan) {
+	sections := []struct {
+		title    string
+		upgrades []Upgrade
+	}{
+		{"Patch upgrades, should be safe to apply", plan.Patch},
+		{"Minor upgrades, may add features or deprecate APIs", plan.Minor},
+		{"Major migrations, require adapting to breaking changes", plan.Major},
+	}
+
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "# %s\n", section.title)
+		if len(section.upgrades) == 0 {
+			fmt.Fprintln(w, "# (none)")
+		}
+		for _, upgrade := range section.upgrades {
+			if upgrade.NewPath != upgrade.Path {
+				fmt.Fprintf(w, "# imports of %s become %s\n", upgrade.Path, upgrade.NewPath)
+			}
+			fmt.Fprintf(w, "%s # from %s", upgrade.Command(), upgrade.From)
+			if upgrade.DaysBehind > 0 {
+				fmt.Fprintf(w, ", %d days behind", upgrade.DaysBehind)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	if len(plan.Skipped) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "# Skipped")
+		for _, skipped := range plan.Skipped {
+			fmt.Fprintf(w, "# %s\n", skipped)
+		}
+	}
+}
+
+// RewriteGoMod applies the patch and minor upgrades of a plan to the requirements of a go.mod file. Major migrations
+// are left out, as they do not build until the import paths are changed.
+func RewriteGoMod(modBytes []byte, plan Plan) ([]byte, error) {
+	modFile, err := modfile.Parse("go.mod", modBytes, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse go.mod")
+	}
+
+	required := make(map[string]bool)
+	for _, require := range modFile.Require {
+		required[require.Mod.Path] = true
+	}
+
+	for _, upgrades := range [][]Upgrade{plan.Patch, plan.Minor} {
+		for _, upgrade := range upgrades {
+			if !required[upgrade.Path] {
+				modFile.AddNewRequire(upgrade.Path, upgrade.To, upgrade.Indirect)
+				continue
+			}
+			if err := modFile.AddRequire(upgrade.Path, upgrade.To); err != nil {
+				return nil, errors.Wrapf(err, "unable to upgrade %s", upgrade.Path)
+			}
+		}
+	}
+
+	modFile.Cleanup()
+	rewritten, err := modFile.Format()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to format go.mod")
+	}
+	return rewritten, nil
+}
@@ -0,0 +1,197 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/module"
+)
+
+func TestBuildPlan(t *testing.T) {
+	report := models.Report{
+		Dependencies: []models.ReportObject{
+			{
+				Name:          "github.com/foo/patch",
+				Installed:     models.VersionDetails{Version: "v1.2.3"},
+				Latest:        models.VersionDetails{Version: "v1.2.5"},
+				LatestInMajor: &models.VersionDetails{Version: "v1.2.5"},
+				Drift:         &models.Drift{DaysBehind: 30},
+				Status:        models.StatusOK,
+			},
+			{
+				Name:          "github.com/foo/both",
+				Installed:     models.VersionDetails{Version: "v1.2.3"},
+				Latest:        models.VersionDetails{Version: "v3.0.0"},
+				LatestInMajor: &models.VersionDetails{Version: "v1.4.0"},
+				NewestMajor:   &models.ModuleVersion{Path: "github.com/foo/both/v3", Version: "v3.0.0"},
+				Indirect:      true,
+				Status:        models.StatusOK,
+			},
+			{
+				Name:        "github.com/foo/legacy",
+				Installed:   models.VersionDetails{Version: "v1.0.0"},
+				NewestMajor: &models.ModuleVersion{Path: "github.com/foo/legacy", Version: "v2.1.0+incompatible"},
+				Status:      models.StatusOK,
+			},
+			{
+				Name:      "github.com/foo/incompatible",
+				Installed: models.VersionDetails{Version: "v2.0.0"},
+				Latest:    models.VersionDetails{Version: "v2.3.0"},
+				Status:    models.StatusOK,
+			},
+			{
+				Name:      "github.com/foo/tagged",
+				Installed: models.VersionDetails{Version: "v0.1.0"},
+				Latest:    models.VersionDetails{Version: "v0.3.0"},
+				Status:    models.StatusOK,
+			},
+			{
+				Name:      "github.com/foo/current",
+				Installed: models.VersionDetails{Version: "v0.0.3-0.20170626215501-b2862e3d0a77"},
+				Latest:    models.VersionDetails{Version: "v0.0.2"},
+				Status:    models.StatusOK,
+			},
+			{
+				Name:      "github.com/foo/replaced",
+				Installed: models.VersionDetails{Version: "v1.0.0"},
+				Latest:    models.VersionDetails{Version: "v1.1.0"},
+				Replace:   &models.Replacement{Effective: models.ModuleVersion{Path: "github.com/fork/replaced", Version: "v1.0.0"}},
+				Status:    models.StatusOK,
+			},
+			{
+				Name:   "github.com/foo/failed",
+				Status: models.StatusFailed,
+			},
+			{
+				Name:   "gopkg.in/fake",
+				Status: models.StatusOK,
+			},
+		},
+	}
+
+	assert.Equal(t, Plan{
+		Patch: []Upgrade{
+			{Path: "github.com/foo/patch", From: "v1.2.3", NewPath: "github.com/foo/patch", To: "v1.2.5", DaysBehind: 30},
+		},
+		Minor: []Upgrade{
+			{Path: "github.com/foo/both", From: "v1.2.3", NewPath: "github.com/foo/both", To: "v1.4.0", Indirect: true},
+			{Path: "github.com/foo/incompatible", From: "v2.0.0+incompatible", NewPath: "github.com/foo/incompatible", To: "v2.3.0+incompatible"},
+			{Path: "github.com/foo/tagged", From: "v0.1.0", NewPath: "github.com/foo/tagged", To: "v0.3.0"},
+		},
+		Major: []Upgrade{
+			{Path: "github.com/foo/both", From: "v1.2.3", NewPath: "github.com/foo/both/v3", To: "v3.0.0", Indirect: true},
+			{Path: "github.com/foo/legacy", From: "v1.0.0", NewPath: "github.com/foo/legacy", To: "v2.1.0+incompatible"},
+		},
+		Skipped: []string{
+			"github.com/foo/replaced: replaced by github.com/fork/replaced",
+			"github.com/foo/failed: not resolved",
+			"gopkg.in/fake: installed version unknown",
+		},
+	}, BuildPlan(report))
+}
+
+func TestFormatPlan(t *testing.T) {
+	plan := Plan{
+		Patch: []Upgrade{
+			{Path: "github.com/foo/patch", From: "v1.2.3", NewPath: "github.com/foo/patch", To: "v1.2.5", DaysBehind: 30},
+		},
+		Major: []Upgrade{
+			{Path: "github.com/foo/both", From: "v1.2.3", NewPath: "github.com/foo/both/v3", To: "v3.0.0"},
+		},
+		Skipped: []string{"github.com/foo/failed: not resolved"},
+	}
+
+	var out bytes.Buffer
+	FormatPlan(&out, plan)
+	assert.Equal(t, `# Patch upgrades, should be safe to apply
+go get github.com/foo/patch@v1.2.5 # from v1.2.3, 30 days behind
+
+# Minor upgrades, may add features or deprecate APIs
+# (none)
+
+# Major migrations, require adapting to breaking changes
+# imports of github.com/foo/both become github.com/foo/both/v3
+go get github.com/foo/both/v3@v3.0.0 # from v1.2.3
+
+# Skipped
+# github.com/foo/failed: not resolved
+`, out.String())
+}
+
+func TestRewriteGoMod(t *testing.T) {
+	goMod := `module example.com/app
+
+go 1.14
+
+require (
+	github.com/foo/both v1.2.3 // indirect
+	github.com/foo/patch v1.2.3
+)
+`
+	plan := Plan{
+		Patch: []Upgrade{
+			{Path: "github.com/foo/patch", From: "v1.2.3", NewPath: "github.com/foo/patch", To: "v1.2.5"},
+		},
+		Minor: []Upgrade{
+			{Path: "github.com/foo/both", From: "v1.2.3", NewPath: "github.com/foo/both", To: "v1.4.0", Indirect: true},
+			{Path: "github.com/foo/transitive", From: "v0.1.0", NewPath: "github.com/foo/transitive", To: "v0.2.0", Indirect: true},
+		},
+		Major: []Upgrade{
+			{Path: "github.com/foo/both", From: "v1.2.3", NewPath: "github.com/foo/both/v3", To: "v3.0.0"},
+		},
+	}
+
+	rewritten, err := RewriteGoMod([]byte(goMod), plan)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `module example.com/app
+
+go 1.14
+
+require (
+	github.com/foo/both v1.4.0 // indirect
+	github.com/foo/patch v1.2.5
+	github.com/foo/transitive v0.2.0 // indirect
+)
+`, string(rewritten))
+	}
+
+	_, err = RewriteGoMod([]byte("not a go.mod"), plan)
+	assert.Error(t, err)
+}
+
+func TestRewriteGoModIncompatible(t *testing.T) {
+	goMod := `module example.com/app
+
+go 1.14
+
+require github.com/foo/legacy v2.0.0+incompatible
+`
+	// The report trims +incompatible from the installed version and the latest tag
+	plan := BuildPlan(models.Report{
+		Dependencies: []models.ReportObject{
+			{
+				Name:      "github.com/foo/legacy",
+				Installed: models.VersionDetails{Version: "v2.0.0"},
+				Latest:    models.VersionDetails{Version: "v2.1.0"},
+				Status:    models.StatusOK,
+			},
+		},
+	})
+
+	if assert.Len(t, plan.Minor, 1) {
+		assert.NoError(t, module.Check(plan.Minor[0].Path, plan.Minor[0].From))
+		assert.NoError(t, module.Check(plan.Minor[0].NewPath, plan.Minor[0].To))
+	}
+
+	rewritten, err := RewriteGoMod([]byte(goMod), plan)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `module example.com/app
+
+go 1.14
+
+require github.com/foo/legacy v2.1.0+incompatible
+`, string(rewritten))
+	}
+}
@@ -1,9 +1,12 @@
 package report
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/1Password/dep-report/models"
@@ -12,37 +15,255 @@ import (
 )
 
 const (
-	GITHUB  = "github"
-	GITLAB  = "gitlab"
-	GERRIT  = "gerrit"
-	UNKNOWN = "unknown/other"
+	GITHUB    = versioncontrol.GITHUB
+	GITLAB    = versioncontrol.GITLAB
+	GERRIT    = versioncontrol.GERRIT
+	BITBUCKET = versioncontrol.BITBUCKET
+	GITEA     = versioncontrol.GITEA
+	GOPROXY   = "goproxy"
+	MODCACHE  = "modcache"
+	LOCAL     = "local"
+	UNKNOWN   = "unknown/other"
 )
 
 // BuildReport This function is used to create the dependency report
 func (g *Generator) BuildReport(productName string, dependencies []models.Dependency) (*models.Report, error) {
-	commit, commitTime, err := getCurrentCommitAndCommitTime()
+	return g.BuildReportContext(context.Background(), productName, dependencies)
+}
+
+// BuildReportContext creates the dependency report, resolving dependencies concurrently.
+// Dependencies are reported in the order they are given, and outstanding lookups are cancelled when ctx is done
+// or when a dependency fails.
+func (g *Generator) BuildReportContext(ctx context.Context, productName string, dependencies []models.Dependency) (*models.Report, error) {
+	commit, err := g.commit()
 	if err != nil {
 		return nil, err
 	}
 
 	report := models.Report{
 		Product:    productName,
-		Commit:     commit,
-		CommitTime: commitTime,
+		Commit:     commit.Hash,
+		CommitTime: commit.Time,
+		Build:      g.Build,
 		ReportTime: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
 	}
 
-	for _, dependency := range dependencies {
-		rObj, err := g.reportObjFromDependency(dependency)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := g.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	limiter := newHostLimiter(g.HostConcurrency)
+	request := g.request.WithContext(ctx)
+	if g.MaxRateLimitWait > 0 {
+		request.Retry.MaxWait = g.MaxRateLimitWait
+	}
+	if g.Cache != nil {
+		request.Cache = g.Cache
+	}
+	request.ExcludePrereleases = g.ExcludePrereleases
+	var verifier *versioncontrol.SumVerifier
+	if g.SumDB != nil {
+		verifier, err = versioncontrol.NewSumVerifier(*g.SumDB, request)
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to create report object from dependency: %v", dependency)
+			return nil, err
 		}
+	}
+
+	if g.DirectOnly {
+		dependencies = directDependencies(dependencies)
+	}
+
+	reportObjects := make([]models.ReportObject, len(dependencies))
+	var firstErr error
+	var errOnce sync.Once
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				rObj, err := g.resolve(ctx, request, limiter, dependencies[i])
+				if err != nil && g.FailureMode == FailFast {
+					errOnce.Do(func() {
+						firstErr = errors.Wrapf(err, "failed to create report object from dependency: %v", dependencies[i])
+						cancel()
+					})
+					continue
+				}
+				if err != nil {
+					rObj = failedReportObject(dependencies[i], err)
+				}
+				annotate(rObj, dependencies[i])
+				if err == nil {
+					releases := g.majorVersions(ctx, request, limiter, rObj, dependencies[i])
+					measureDrift(rObj, releases)
+				}
+				if verifier != nil {
+					verifySums(ctx, verifier, limiter, rObj, dependencies[i])
+				}
+				reportObjects[i] = *rObj
+			}
+		}()
+	}
+
+feed:
+	for i := range dependencies {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
 
-		report.Dependencies = append(report.Dependencies, *rObj)
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(err, "report generation was cancelled")
+	}
+
+	report.Dependencies = reportObjects
+	report.Summary = summarize(reportObjects)
+	if g.FailureMode == FailAtEnd && report.Summary.Failed > 0 {
+		return &report, errors.Wrapf(ErrDependenciesFailed, "%d of %d dependencies", report.Summary.Failed, report.Summary.Total)
 	}
 	return &report, nil
 }
 
+// annotate copies what the dependency files tell about a dependency, rather than its host, onto its report object
+func annotate(reportObject *models.ReportObject, dep models.Dependency) {
+	reportObject.Indirect = dep.Indirect
+	reportObject.RequiredBy = dep.RequiredBy
+	reportObject.Why = dep.Why
+	reportObject.UsedBy = dep.UsedBy
+	reportObject.Implicit = dep.Implicit
+	reportObject.Packages = dep.Packages
+	reportObject.Sum = dep.Sum
+	reportObject.GoModSum = dep.GoModSum
+	// go.sum and binaries record the hashes of the module that replaced a dependency
+	if dep.Replace != nil && reportObject.Sum == "" && reportObject.GoModSum == "" {
+		reportObject.Sum = dep.Replace.Sum
+		reportObject.GoModSum = dep.Replace.GoModSum
+	}
+	reportObject.Warnings = dep.Warnings
+}
+
+// verifySums checks the hashes of a dependency against the checksum database. Lookups that fail are reported as
+// warnings rather than failing the dependency, since the hashes are not needed to resolve it.
+func verifySums(ctx context.Context, verifier *versioncontrol.SumVerifier, limiter *hostLimiter, reportObject *models.ReportObject, dep models.Dependency) {
+	hashed := dep
+	if dep.Replace != nil && dep.Replace.Version != "" {
+		hashed = *dep.Replace
+	}
+	if hashed.Path == "" || hashed.Version == "" {
+		return
+	}
+
+	mod := hashed.Path + "@" + hashed.Version
+	release, err := limiter.acquire(ctx, verifier.Host())
+	if err != nil {
+		reportObject.Warnings = append(reportObject.Warnings, fmt.Sprintf("unable to verify the hashes of %s: %v", mod, err))
+		return
+	}
+	defer release()
+
+	result, err := verifier.Verify(hashed.Path, versioncontrol.IncompatibleVersion(hashed.Path, hashed.Version), reportObject.Sum, reportObject.GoModSum)
+	if err != nil {
+		reportObject.Warnings = append(reportObject.Warnings, fmt.Sprintf("unable to verify the hashes of %s: %v", mod, err))
+		return
+	}
+	reportObject.SumDB = result
+	if result == versioncontrol.SumMismatch {
+		reportObject.Warnings = append(reportObject.Warnings, "the hashes of "+mod+" do not match the checksum database")
+	}
+}
+
+// majorVersions looks up the latest version in the major version line of a dependency and its newest major version,
+// through the module proxy or offline in the module cache, and returns the releases of the module found on the way.
+// Lookups that fail are reported as warnings.
+func (g Generator) majorVersions(ctx context.Context, request versioncontrol.Client, limiter *hostLimiter, reportObject *models.ReportObject, dep models.Dependency) []string {
+	if dep.Replace != nil {
+		dep = *dep.Replace
+	}
+	if dep.Path == "" || dep.Version == "" {
+		return nil
+	}
+
+	var majors versioncontrol.MajorVersions
+	var err error
+	if g.Offline {
+		majors, err = request.ModCache.MajorVersions(dep.Path, dep.Version)
+	} else {
+		var release func()
+		release, err = limiter.acquire(ctx, request.GoProxy.Host(dep.Path))
+		if err == nil {
+			majors, err = request.MajorVersions(dep.Path, dep.Version)
+			release()
+		}
+	}
+	if err != nil {
+		reportObject.Warnings = append(reportObject.Warnings, fmt.Sprintf("unable to look up the major versions of %s: %v", dep.Path, err))
+		return nil
+	}
+
+	if majors.Latest.Version != "" {
+		reportObject.LatestInMajor = &majors.Latest
+	}
+	if majors.Newest.Path != "" {
+		reportObject.NewestMajor = &majors.Newest
+	}
+	return majors.Releases
+}
+
+// failedReportObject records a dependency that could not be resolved, with the local data we have
+func failedReportObject(dep models.Dependency, err error) *models.ReportObject {
+	return &models.ReportObject{
+		Name:   dep.Name,
+		Source: UNKNOWN,
+		Installed: models.VersionDetails{
+			Version: dep.Version,
+		},
+		Status: models.StatusFailed,
+		Errors: []string{err.Error()},
+	}
+}
+
+// directDependencies drops the dependencies go.mod marks as indirect
+func directDependencies(dependencies []models.Dependency) []models.Dependency {
+	var direct []models.Dependency
+	for _, dep := range dependencies {
+		if !dep.Indirect {
+			direct = append(direct, dep)
+		}
+	}
+	return direct
+}
+
+func summarize(reportObjects []models.ReportObject) models.Summary {
+	summary := models.Summary{Total: len(reportObjects)}
+	for _, reportObject := range reportObjects {
+		if reportObject.Status == models.StatusFailed {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+		}
+		if reportObject.Indirect {
+			summary.Indirect++
+		} else {
+			summary.Direct++
+		}
+		countGap(&summary.Drift, reportObject.Drift)
+	}
+	return summary
+}
+
 // FormatReport takes a report struct and formats it into pretty json
 func FormatReport(rawReport models.Report) ([]byte, error) {
 	prettyReport, err := json.MarshalIndent(rawReport, "", "  ")
@@ -53,6 +274,29 @@ func FormatReport(rawReport models.Report) ([]byte, error) {
 	return prettyReport, nil
 }
 
+// FormatReports formats the reports of several modules into one pretty json document keyed by module path
+func FormatReports(rawReports map[string]models.Report) ([]byte, error) {
+	prettyReports, err := json.MarshalIndent(rawReports, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to marshal indent reports")
+	}
+
+	return prettyReports, nil
+}
+
+// commit returns the commit the report is for, the HEAD commit of the working directory unless one is configured
+func (g *Generator) commit() (Commit, error) {
+	if g.Commit != nil {
+		return *g.Commit, nil
+	}
+
+	hash, time, err := getCurrentCommitAndCommitTime()
+	if err != nil {
+		return Commit{}, err
+	}
+	return Commit{Hash: hash, Time: time}, nil
+}
+
 func getCurrentCommitAndCommitTime() (string, string, error) {
 	commitBytes, err := exec.Command("git", "rev-parse", "HEAD").Output()
 	if err != nil {
@@ -71,57 +315,145 @@ func getCurrentCommitAndCommitTime() (string, string, error) {
 	return commit, commitTime, nil
 }
 
-func (g Generator) reportObjFromDependency(dep models.Dependency) (*models.ReportObject, error) {
-	dep.Source = determineSource(dep.Name)
-
-	var reportObject *models.ReportObject
-	var err error
+func (g Generator) reportObjFromDependency(ctx context.Context, request versioncontrol.Client, limiter *hostLimiter, dep models.Dependency) (*models.ReportObject, error) {
+	if dep.Replace != nil {
+		return g.reportObjFromReplacement(ctx, request, limiter, dep)
+	}
 
-	// For GitHub and Gerrit, we perform an online lookup to determine the latest version of a package
-	// For all other packages, we can't determine upstream versions and just report the local data we have
-	switch dep.Source {
-	case GITHUB:
-		reportObject, err = versioncontrol.ReportObjFromGithub(dep, g.request)
+	// Offline, everything comes from the module cache and no repo is resolved
+	if g.Offline {
+		dep.Source = MODCACHE
+		reportObject, err := versioncontrol.ReportObjFromModCache(dep, request)
 		if err != nil {
 			return nil, errors.Wrapf(err, "unable to generate reportObject from dependency %s", dep.Name)
 		}
-	case GERRIT:
-		reportObject, err = versioncontrol.ReportObjFromGerrit(dep, g.request)
+		reportObject.Status = models.StatusOK
+		return reportObject, nil
+	}
+
+	var err error
+	dep.Repo, dep.Subdir, err = g.repoForPackage(ctx, request, limiter, dep.RootPath())
+	if err != nil {
+		return nil, err
+	}
+
+	// Packages on a host with a registered provider get an online lookup to determine the latest version.
+	// For all other packages, we ask the module proxy, and when no proxy can serve the module
+	// we can't determine upstream versions and just report the local data we have
+	if provider, ok := g.providers().Lookup(dep, request); ok {
+		release, err := limiter.acquire(ctx, dependencyHost(dep))
 		if err != nil {
-			return nil, errors.Wrapf(err, "unable to generate reportObject from dependency %s", dep.Name)
+			return nil, err
 		}
-	default:
-		reportObject, err = versioncontrol.ReportObjGeneric(dep)
+		defer release()
+
+		dep.Source = provider.Name()
+		reportObject, err := versioncontrol.ReportObjFromProvider(provider, dep, request)
 		if err != nil {
 			return nil, errors.Wrapf(err, "unable to generate reportObject from dependency %s", dep.Name)
 		}
+		reportObject.Status = models.StatusOK
+		return reportObject, nil
+	}
+
+	dep.Source = UNKNOWN
+	proxyDep := dep
+	proxyDep.Source = GOPROXY
+	release, err := limiter.acquire(ctx, request.GoProxy.Host(dep.Path))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
+	reportObject, err := versioncontrol.ReportObjFromGoProxy(proxyDep, request)
+	if errors.Cause(err) == versioncontrol.ErrNoProxy {
+		reportObject, err = versioncontrol.ReportObjGeneric(dep)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to generate reportObject from dependency %s", dep.Name)
 	}
+	reportObject.Status = models.StatusOK
 
 	return reportObject, nil
 }
 
-func determineSource(packageName string) string {
-	repo := packageName
+// reportObjFromReplacement resolves the module replacing a dependency and reports it under the name of the dependency.
+// Directory replacements are reported as local without any lookup.
+func (g Generator) reportObjFromReplacement(ctx context.Context, request versioncontrol.Client, limiter *hostLimiter, dep models.Dependency) (*models.ReportObject, error) {
+	replacement := &models.Replacement{
+		Original:  models.ModuleVersion{Path: dep.Path, Version: dep.Version},
+		Effective: models.ModuleVersion{Path: dep.Replace.Path, Version: dep.Replace.Version},
+	}
 
-	if url, ok := versioncontrol.GithubRepoURLForPackage[packageName]; ok {
-		repo = url
+	if dep.Replace.Version == "" {
+		replacement.Local = true
+		return &models.ReportObject{
+			Name:    dep.Name,
+			Source:  LOCAL,
+			Status:  models.StatusOK,
+			Replace: replacement,
+		}, nil
 	}
 
-	switch {
-	case strings.Contains(repo, GITHUB):
-		if strings.Contains(repo, "repo") {
-			return GERRIT
-		} else {
-			return GITHUB
-		}
-	case strings.Contains(repo, "1password.io"):
-		return GITLAB
-	case strings.Contains(repo, "googlesource"):
-		return GERRIT
-	case strings.Contains(repo, "golang.org/x"):
-		return GERRIT
-	default:
-		return UNKNOWN
+	reportObject, err := g.reportObjFromDependency(ctx, request, limiter, *dep.Replace)
+	if err != nil {
+		return nil, err
+	}
+	reportObject.Name = dep.Name
+	reportObject.Replace = replacement
+
+	return reportObject, nil
+}
+
+// repoForPackage returns the URL of the repository hosting a package and the directory of the package in it.
+// Entries in the package maps take precedence, packages on hosts with a registered provider are used as is and
+// anything else is resolved through its go-get meta tags.
+// GitLab packages are resolved as well because nested groups make the project path ambiguous.
+// The go-get request counts against the concurrency limit of the package host, and only fails when ctx is done.
+func (g Generator) repoForPackage(ctx context.Context, request versioncontrol.Client, limiter *hostLimiter, packageName string) (string, string, error) {
+	if repoURL, ok := versioncontrol.GerritRepoURLForPackage[packageName]; ok {
+		return repoURL, "", nil
+	}
+	if repoURL, ok := versioncontrol.GithubRepoURLForPackage[packageName]; ok {
+		return repoURL, "", nil
+	}
+	// The golang.org/x repositories are served from go.googlesource.com, as their go-get meta tags say
+	if strings.HasPrefix(packageName, "golang.org/x/") {
+		elems := strings.SplitN(strings.TrimPrefix(packageName, "golang.org/x/"), "/", 2)
+		return "https://go.googlesource.com/" + elems[0], subdir(elems, 1), nil
+	}
+
+	// Repositories on hosts with a provider are named {owner}/{project}, anything below is a directory
+	repo := "https://" + packageName
+	if provider, ok := g.providers().Lookup(models.Dependency{Name: packageName, Repo: repo}, request); ok && provider.Name() != GITLAB {
+		return repo, subdir(strings.SplitN(packageName, "/", 4), 3), nil
+	}
+
+	release, err := limiter.acquire(ctx, dependencyHost(models.Dependency{Name: packageName}))
+	if err != nil {
+		return "", "", err
+	}
+	defer release()
+
+	root, err := request.ResolveImportPath(packageName)
+	if err != nil {
+		return repo, "", nil
+	}
+	return root.RepoURL, strings.TrimPrefix(strings.TrimPrefix(packageName, root.Prefix), "/"), nil
+}
+
+// subdir returns the path element at index n of a path split with SplitN, which holds the rest of the path
+func subdir(elems []string, n int) string {
+	if len(elems) <= n {
+		return ""
+	}
+	return elems[n]
+}
+
+// providers returns the registry used to look up the provider of a dependency
+func (g Generator) providers() *versioncontrol.Registry {
+	if g.registry == nil {
+		return versioncontrol.DefaultRegistry()
 	}
+	return g.registry
 }
@@ -1,23 +1,46 @@
 package report
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
 	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/1Password/dep-report/internal/fixture"
 	"github.com/1Password/dep-report/models"
 	"github.com/1Password/dep-report/versioncontrol"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/note"
 )
 
 var githubToken = flag.String("githubToken", "", "Token to be used in Github requests")
 
+// githubTagFixtures serves the tags of the GitHub repositories in generateReport.yaml, which was recorded before tags were
+// listed. They are no longer needed once the cassette is recorded again with -githubToken.
+var githubTagFixtures = map[string]string{
+	"https://api.github.com/repos/go-check/check/tags?per_page=100&page=1":        `[]`,
+	"https://api.github.com/repos/xordataexchange/crypt/tags?per_page=100&page=1": `[{"name":"v0.0.2"},{"name":"v0.0.1"}]`,
+	"https://api.github.com/repos/pkg/errors/tags?per_page=100&page=1":            `[{"name":"v0.9.1"},{"name":"v0.9.0"},{"name":"v0.8.1"},{"name":"v0.8.0"},{"name":"v0.7.1"},{"name":"v0.7.0"},{"name":"v0.6.0"},{"name":"v0.5.1"},{"name":"v0.5.0"},{"name":"v0.4.0"},{"name":"v0.3.0"},{"name":"v0.2.0"},{"name":"v0.1.0"}]`,
+	"https://api.github.com/repos/BurntSushi/toml/tags?per_page=100&page=1":       `[{"name":"v0.3.1"},{"name":"v0.3.0"},{"name":"v0.2.0"},{"name":"v0.1.0"}]`,
+}
+
 func TestGenerateReport(t *testing.T) {
 	r, c, err := versioncontrol.SetupHTTPRecord("generateReport")
 	if err != nil {
 		t.Fatalf("unable to setup test recorder: %v", err)
 	}
 	defer r.Stop()
+	c.Transport = fixture.Transport{Responses: githubTagFixtures, Next: c.Transport}
 
 	g := Generator{
 		request: versioncontrol.Client{
@@ -67,12 +90,14 @@ func TestGenerateReport(t *testing.T) {
 				ReportTime: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
 				Commit:     "77ae4af8d07bcd816b0f14bdf26cb074f0cfa8b9",
 				CommitTime: "2020-04-22T11:02:24-06:00",
+				Summary:    models.Summary{Total: 6, Succeeded: 6, Direct: 6, Drift: models.GapHistogram{Unknown: 6}},
 				Dependencies: []models.ReportObject{
 					{
 						Name:    "gopkg.in/check.v1",
 						Source:  "github",
 						License: "NOASSERTION",
 						Website: "https://api.github.com/repos/go-check/check",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2018-06-28T17:31:08Z",
 							Commit: "788fd78401277ebd861206a03c884797c6ec5541",
@@ -81,12 +106,15 @@ func TestGenerateReport(t *testing.T) {
 							Time:   "2020-02-27T12:52:54Z",
 							Commit: "8fa46927fb4f5b54d48bde78c6c08db205b2298c",
 						},
+						Drift:        &models.Drift{DaysBehind: 608},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 					{
 						Name:    "golang.org/x/text",
 						Source:  "gerrit",
 						License: "BSD-3-Clause",
 						Website: "https://go-review.googlesource.com/projects/text",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2019-04-25T21:42:06Z",
 							Commit: "342b2e1fbaa52c93f31447ad2c6abc048c63e475",
@@ -96,41 +124,51 @@ func TestGenerateReport(t *testing.T) {
 							Time:    "2020-03-06T15:41:05Z",
 							Commit:  "06d492aade888ab8698aad35476286b7b555c961",
 						},
+						Drift:        &models.Drift{DaysBehind: 315},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 					{
 						Name:    "cloud.google.com/go",
 						Source:  "gerrit",
 						License: "NOASSERTION",
 						Website: "https://code-review.googlesource.com/projects/gocloud",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2020-03-05T18:01:17Z",
 							Commit: "a6b88cf34a491498e4c7d15c107a31058693e2cb",
 						},
 						Latest: models.VersionDetails{
-							Version: "v0.9.0",
+							Version: "v0.56.0",
 							Time:    "2020-04-23T00:31:42Z",
 							Commit:  "c9d3eadce82c530f46cf3c09fc607e329affe4b2",
 						},
+						Drift:        &models.Drift{DaysBehind: 48},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 					{
 						Name:    "github.com/xordataexchange/crypt",
 						Source:  "github",
 						License: "MIT",
 						Website: "https://api.github.com/repos/xordataexchange/crypt",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2017-06-26T21:55:01Z",
 							Commit: "b2862e3d0a775f18c7cfe02273500ae307b61218",
 						},
 						Latest: models.VersionDetails{
-							Time:   "2017-06-26T21:55:01Z",
-							Commit: "b2862e3d0a775f18c7cfe02273500ae307b61218",
+							Version: "v0.0.2",
+							Time:    "2017-06-26T21:55:01Z",
+							Commit:  "b2862e3d0a775f18c7cfe02273500ae307b61218",
 						},
+						Drift:        &models.Drift{DaysBehind: 0},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 					{
 						Name:    "github.com/pkg/errors",
 						Source:  "github",
 						License: "BSD-2-Clause",
 						Website: "https://api.github.com/repos/pkg/errors",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2019-01-03T06:52:24Z",
 							Commit: "ba968bfe8b2f7e042a574c888954fccecfa385b4",
@@ -140,20 +178,26 @@ func TestGenerateReport(t *testing.T) {
 							Time:    "2020-01-14T19:47:44Z",
 							Commit:  "614d223910a179a466c1767a985424175c39b465",
 						},
+						Drift:        &models.Drift{DaysBehind: 376},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 					{
 						Name:    "github.com/BurntSushi/toml",
 						Source:  "github",
 						License: "MIT",
 						Website: "https://api.github.com/repos/BurntSushi/toml",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2018-08-15T10:47:33Z",
 							Commit: "3012a1dbe2e4bd1391d42b32f0577cb7bbc7f005",
 						},
 						Latest: models.VersionDetails{
-							Time:   "2018-08-15T10:47:33Z",
-							Commit: "3012a1dbe2e4bd1391d42b32f0577cb7bbc7f005",
+							Version: "v0.3.1",
+							Time:    "2018-08-15T10:47:33Z",
+							Commit:  "3012a1dbe2e4bd1391d42b32f0577cb7bbc7f005",
 						},
+						Drift:        &models.Drift{DaysBehind: 0},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 				},
 			},
@@ -191,12 +235,14 @@ func TestGenerateReport(t *testing.T) {
 				ReportTime: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
 				Commit:     "77ae4af8d07bcd816b0f14bdf26cb074f0cfa8b9",
 				CommitTime: "2020-04-22T11:02:24-06:00",
+				Summary:    models.Summary{Total: 6, Succeeded: 6, Direct: 6, Drift: models.GapHistogram{Unknown: 6}},
 				Dependencies: []models.ReportObject{
 					{
 						Name:    "gopkg.in/check.v1",
 						Source:  "github",
 						License: "NOASSERTION",
 						Website: "https://api.github.com/repos/go-check/check",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2018-06-28T17:31:08Z",
 							Commit: "788fd78401277ebd861206a03c884797c6ec5541",
@@ -205,12 +251,15 @@ func TestGenerateReport(t *testing.T) {
 							Time:   "2020-02-27T12:52:54Z",
 							Commit: "8fa46927fb4f5b54d48bde78c6c08db205b2298c",
 						},
+						Drift:        &models.Drift{DaysBehind: 608},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 					{
 						Name:    "golang.org/x/text",
 						Source:  "gerrit",
 						License: "BSD-3-Clause",
 						Website: "https://go-review.googlesource.com/projects/text",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2019-04-25T21:42:06Z",
 							Commit: "342b2e1fbaa52c93f31447ad2c6abc048c63e475",
@@ -220,41 +269,51 @@ func TestGenerateReport(t *testing.T) {
 							Time:    "2020-03-06T15:41:05Z",
 							Commit:  "06d492aade888ab8698aad35476286b7b555c961",
 						},
+						Drift:        &models.Drift{DaysBehind: 315},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 					{
 						Name:    "cloud.google.com/go",
 						Source:  "gerrit",
 						License: "NOASSERTION",
 						Website: "https://code-review.googlesource.com/projects/gocloud",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2020-03-05T18:01:17Z",
 							Commit: "a6b88cf34a491498e4c7d15c107a31058693e2cb",
 						},
 						Latest: models.VersionDetails{
-							Version: "v0.9.0",
+							Version: "v0.56.0",
 							Time:    "2020-04-23T00:31:42Z",
 							Commit:  "c9d3eadce82c530f46cf3c09fc607e329affe4b2",
 						},
+						Drift:        &models.Drift{DaysBehind: 48},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 					{
 						Name:    "github.com/xordataexchange/crypt",
 						Source:  "github",
 						License: "MIT",
 						Website: "https://api.github.com/repos/xordataexchange/crypt",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2017-06-26T21:55:01Z",
 							Commit: "b2862e3d0a775f18c7cfe02273500ae307b61218",
 						},
 						Latest: models.VersionDetails{
-							Time:   "2017-06-26T21:55:01Z",
-							Commit: "b2862e3d0a775f18c7cfe02273500ae307b61218",
+							Version: "v0.0.2",
+							Time:    "2017-06-26T21:55:01Z",
+							Commit:  "b2862e3d0a775f18c7cfe02273500ae307b61218",
 						},
+						Drift:        &models.Drift{DaysBehind: 0},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 					{
 						Name:    "github.com/pkg/errors",
 						Source:  "github",
 						License: "BSD-2-Clause",
 						Website: "https://api.github.com/repos/pkg/errors",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2016-09-29T01:48:01Z",
 							Commit: "645ef00459ed84a119197bfb8d8205042c6df63d",
@@ -264,20 +323,26 @@ func TestGenerateReport(t *testing.T) {
 							Time:    "2020-01-14T19:47:44Z",
 							Commit:  "614d223910a179a466c1767a985424175c39b465",
 						},
+						Drift:        &models.Drift{DaysBehind: 1202},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 					{
 						Name:    "github.com/BurntSushi/toml",
 						Source:  "github",
 						License: "MIT",
 						Website: "https://api.github.com/repos/BurntSushi/toml",
+						Status:  "ok",
 						Installed: models.VersionDetails{
 							Time:   "2018-08-15T10:47:33Z",
 							Commit: "3012a1dbe2e4bd1391d42b32f0577cb7bbc7f005",
 						},
 						Latest: models.VersionDetails{
-							Time:   "2018-08-15T10:47:33Z",
-							Commit: "3012a1dbe2e4bd1391d42b32f0577cb7bbc7f005",
+							Version: "v0.3.1",
+							Time:    "2018-08-15T10:47:33Z",
+							Commit:  "3012a1dbe2e4bd1391d42b32f0577cb7bbc7f005",
 						},
+						Drift:        &models.Drift{DaysBehind: 0},
+						Undetermined: []string{"drift.versionsBehind", "drift.gap"},
 					},
 				},
 			},
@@ -296,10 +361,12 @@ func TestGenerateReport(t *testing.T) {
 				ReportTime: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
 				Commit:     "77ae4af8d07bcd816b0f14bdf26cb074f0cfa8b9",
 				CommitTime: "2020-04-22T11:02:24-06:00",
+				Summary:    models.Summary{Total: 1, Succeeded: 1, Direct: 1, Drift: models.GapHistogram{Unknown: 1}},
 				Dependencies: []models.ReportObject{
 					{
 						Name:   "gopkg.in/fake",
 						Source: "unknown/other",
+						Status: "ok",
 					},
 				},
 			},
@@ -330,3 +397,392 @@ func TestGenerateReport(t *testing.T) {
 		})
 	}
 }
+
+// offlineTransport fails every request, so dependencies on unknown hosts are reported with local data only
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("offline")
+}
+
+func TestBuildReportContext(t *testing.T) {
+	var dependencies []models.Dependency
+	var wantDependencies []models.ReportObject
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("example%d.invalid/pkg%d", i%7, i)
+		dependencies = append(dependencies, models.Dependency{Name: name, Version: "v1.0.0"})
+		wantDependencies = append(wantDependencies, models.ReportObject{
+			Name:      name,
+			Source:    "unknown/other",
+			Installed: models.VersionDetails{Version: "v1.0.0"},
+			Status:    "ok",
+		})
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		description      string
+		ctx              context.Context
+		workers          int
+		hostConcurrency  int
+		wantDependencies []models.ReportObject
+		wantError        bool
+	}{
+		{
+			description:      "should keep the input order with many workers",
+			ctx:              context.Background(),
+			workers:          16,
+			hostConcurrency:  2,
+			wantDependencies: wantDependencies,
+		},
+		{
+			description:      "should keep the input order with a single worker",
+			ctx:              context.Background(),
+			workers:          1,
+			wantDependencies: wantDependencies,
+		},
+		{
+			description: "should return an error when the context is cancelled",
+			ctx:         cancelled,
+			workers:     4,
+			wantError:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			g := Generator{
+				request: versioncontrol.Client{
+					HttpClient: &http.Client{Transport: offlineTransport{}},
+					Retry:      versioncontrol.Retry{BaseDelay: time.Millisecond},
+				},
+				Workers:         test.workers,
+				HostConcurrency: test.hostConcurrency,
+			}
+
+			gotReport, err := g.BuildReportContext(test.ctx, "dep-report", dependencies)
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.wantDependencies, gotReport.Dependencies)
+			}
+		})
+	}
+}
+
+func TestBuildReportOffline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dep-report-modcache")
+	if err != nil {
+		t.Fatalf("unable to create module cache: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	g := Generator{
+		request: versioncontrol.Client{
+			HttpClient: &http.Client{Transport: offlineTransport{}},
+			ModCache:   versioncontrol.ModCache{Dir: dir},
+		},
+		Offline: true,
+	}
+
+	gotReport, err := g.BuildReport("dep-report", []models.Dependency{
+		{Name: "github.com/pkg/errors", Path: "github.com/pkg/errors", Revision: "v0.8.1", Version: "v0.8.1"},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []models.ReportObject{
+			{
+				Name:         "github.com/pkg/errors",
+				Source:       "modcache",
+				Website:      "https://pkg.go.dev/github.com/pkg/errors",
+				Installed:    models.VersionDetails{Version: "v0.8.1"},
+				Status:       "ok",
+				Undetermined: []string{"installed.time", "latest", "license"},
+			},
+		}, gotReport.Dependencies)
+	}
+}
+
+func TestBuildReportReplace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dep-report-modcache")
+	if err != nil {
+		t.Fatalf("unable to create module cache: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	g := Generator{
+		request: versioncontrol.Client{
+			HttpClient: &http.Client{Transport: offlineTransport{}},
+			ModCache:   versioncontrol.ModCache{Dir: dir},
+		},
+		Offline: true,
+	}
+
+	gotReport, err := g.BuildReport("dep-report", []models.Dependency{
+		{
+			Name:     "github.com/foo/bar",
+			Path:     "github.com/foo/bar",
+			Revision: "v1.0.0",
+			Version:  "v1.0.0",
+			Replace:  &models.Dependency{Name: "github.com/ourfork/bar", Path: "github.com/ourfork/bar", Revision: "v1.2.3", Version: "v1.2.3"},
+		},
+		{
+			Name:     "github.com/foo/baz",
+			Path:     "github.com/foo/baz",
+			Revision: "v1.1.0",
+			Version:  "v1.1.0",
+			Replace:  &models.Dependency{Name: "../baz", Path: "../baz"},
+		},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []models.ReportObject{
+			{
+				Name:         "github.com/foo/bar",
+				Source:       "modcache",
+				Website:      "https://pkg.go.dev/github.com/ourfork/bar",
+				Installed:    models.VersionDetails{Version: "v1.2.3"},
+				Status:       "ok",
+				Undetermined: []string{"installed.time", "latest", "license"},
+				Replace: &models.Replacement{
+					Original:  models.ModuleVersion{Path: "github.com/foo/bar", Version: "v1.0.0"},
+					Effective: models.ModuleVersion{Path: "github.com/ourfork/bar", Version: "v1.2.3"},
+				},
+			},
+			{
+				Name:   "github.com/foo/baz",
+				Source: "local",
+				Status: "ok",
+				Replace: &models.Replacement{
+					Original:  models.ModuleVersion{Path: "github.com/foo/baz", Version: "v1.1.0"},
+					Effective: models.ModuleVersion{Path: "../baz"},
+					Local:     true,
+				},
+			},
+		}, gotReport.Dependencies)
+	}
+}
+
+func TestBuildReportIndirect(t *testing.T) {
+	dependencies := []models.Dependency{
+		{Name: "example.invalid/direct", Version: "v1.0.0"},
+		{Name: "example.invalid/indirect", Version: "v1.1.0", Indirect: true},
+	}
+
+	tests := []struct {
+		description      string
+		directOnly       bool
+		wantSummary      models.Summary
+		wantDependencies []models.ReportObject
+	}{
+		{
+			description: "should report and count indirect dependencies",
+			wantSummary: models.Summary{Total: 2, Succeeded: 2, Direct: 1, Indirect: 1, Drift: models.GapHistogram{Unknown: 2}},
+			wantDependencies: []models.ReportObject{
+				{Name: "example.invalid/direct", Source: "unknown/other", Installed: models.VersionDetails{Version: "v1.0.0"}, Status: "ok"},
+				{Name: "example.invalid/indirect", Source: "unknown/other", Installed: models.VersionDetails{Version: "v1.1.0"}, Indirect: true, Status: "ok"},
+			},
+		},
+		{
+			description: "should leave indirect dependencies out when only direct ones are wanted",
+			directOnly:  true,
+			wantSummary: models.Summary{Total: 1, Succeeded: 1, Direct: 1, Drift: models.GapHistogram{Unknown: 1}},
+			wantDependencies: []models.ReportObject{
+				{Name: "example.invalid/direct", Source: "unknown/other", Installed: models.VersionDetails{Version: "v1.0.0"}, Status: "ok"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			g := Generator{
+				request: versioncontrol.Client{
+					HttpClient: &http.Client{Transport: offlineTransport{}},
+					Retry:      versioncontrol.Retry{BaseDelay: time.Millisecond},
+				},
+				DirectOnly: test.directOnly,
+			}
+
+			gotReport, err := g.BuildReport("dep-report", dependencies)
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.wantSummary, gotReport.Summary)
+				assert.Equal(t, test.wantDependencies, gotReport.Dependencies)
+			}
+		})
+	}
+}
+
+func TestBuildReportMajorVersions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dep-report-modcache")
+	if err != nil {
+		t.Fatalf("unable to create module cache: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"github.com/foo/bar/@v/list":           "v1.0.0\nv1.1.0\n",
+		"github.com/foo/bar/@v/v1.1.0.info":    `{"Version":"v1.1.0","Time":"2020-01-01T00:00:00Z"}`,
+		"github.com/foo/bar/v2/@v/list":        "v2.0.0\n",
+		"github.com/foo/bar/v2/@v/v2.0.0.info": `{"Version":"v2.0.0","Time":"2021-01-01T00:00:00Z"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, "cache", "download", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("unable to create module cache: %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("unable to create module cache: %v", err)
+		}
+	}
+
+	g := Generator{
+		request: versioncontrol.Client{
+			HttpClient: &http.Client{Transport: offlineTransport{}},
+			ModCache:   versioncontrol.ModCache{Dir: dir},
+		},
+		Offline: true,
+	}
+
+	gotReport, err := g.BuildReport("dep-report", []models.Dependency{
+		{Name: "github.com/foo/bar", Path: "github.com/foo/bar", Root: "github.com/foo/bar", Revision: "v1.0.0", Version: "v1.0.0"},
+		{Name: "github.com/foo/bar/v2", Path: "github.com/foo/bar/v2", Root: "github.com/foo/bar", Revision: "v2.0.0", Version: "v2.0.0"},
+	})
+	if assert.NoError(t, err) {
+		assert.Len(t, gotReport.Dependencies, 2)
+		v1, v2 := gotReport.Dependencies[0], gotReport.Dependencies[1]
+
+		assert.Equal(t, "github.com/foo/bar", v1.Name)
+		assert.Equal(t, &models.VersionDetails{Version: "v1.1.0", Time: "2020-01-01T00:00:00Z"}, v1.LatestInMajor)
+		assert.Equal(t, &models.ModuleVersion{Path: "github.com/foo/bar/v2", Version: "v2.0.0"}, v1.NewestMajor)
+
+		assert.Equal(t, "github.com/foo/bar/v2", v2.Name)
+		assert.Equal(t, &models.VersionDetails{Version: "v2.0.0", Time: "2021-01-01T00:00:00Z"}, v2.LatestInMajor)
+		assert.Nil(t, v2.NewestMajor)
+	}
+}
+
+func TestRepoForPackage(t *testing.T) {
+	g := Generator{}
+	request := versioncontrol.Client{HttpClient: &http.Client{Transport: offlineTransport{}}}
+
+	tests := []struct {
+		packageName string
+		wantRepo    string
+		wantSubdir  string
+	}{
+		{"github.com/foo/bar", "https://github.com/foo/bar", ""},
+		{"github.com/foo/bar/sub/module", "https://github.com/foo/bar/sub/module", "sub/module"},
+		{"golang.org/x/text", "https://go.googlesource.com/text", ""},
+		{"golang.org/x/tools/gopls", "https://go.googlesource.com/tools", "gopls"},
+		{"cloud.google.com/go", "https://code-review.googlesource.com/projects/gocloud", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.packageName, func(t *testing.T) {
+			repo, subdir, err := g.repoForPackage(context.Background(), request, nil, test.packageName)
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.wantRepo, repo)
+				assert.Equal(t, test.wantSubdir, subdir)
+			}
+		})
+	}
+
+	// go-get lookups wait for a slot on the package host
+	limiter := newHostLimiter(1)
+	release, err := limiter.acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unable to acquire slot: %v", err)
+	}
+	defer release()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = g.repoForPackage(ctx, request, limiter, "example.com/vanity")
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestBuildReportCommit(t *testing.T) {
+	g := Generator{
+		request: versioncontrol.Client{
+			HttpClient: &http.Client{Transport: offlineTransport{}},
+		},
+		Commit: &Commit{Hash: "614d223910a179a466c1767a985424175c39b465", Time: "2020-01-14T19:47:44Z"},
+		Build:  &models.Build{GoVersion: "go1.21.0", Main: models.ModuleVersion{Path: "github.com/1Password/example", Version: "(devel)"}},
+	}
+
+	gotReport, err := g.BuildReport("dep-report", nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "614d223910a179a466c1767a985424175c39b465", gotReport.Commit)
+		assert.Equal(t, "2020-01-14T19:47:44Z", gotReport.CommitTime)
+		assert.Equal(t, &models.Build{GoVersion: "go1.21.0", Main: models.ModuleVersion{Path: "github.com/1Password/example", Version: "(devel)"}}, gotReport.Build)
+	}
+}
+
+// hostTransport only reaches the given host, so dependencies on other hosts are reported with local data only
+type hostTransport struct {
+	host string
+}
+
+func (t hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.host {
+		return nil, errors.New("offline")
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBuildReportVerifySums(t *testing.T) {
+	signer, key, err := note.GenerateKey(rand.Reader, "sum.example.test")
+	if err != nil {
+		t.Fatalf("unable to generate checksum database key: %v", err)
+	}
+	server := httptest.NewServer(sumdb.NewServer(sumdb.NewTestServer(signer, func(path, vers string) ([]byte, error) {
+		return []byte(fmt.Sprintf("%s %s h1:good=\n%s %s/go.mod h1:goodmod=\n", path, vers, path, vers)), nil
+	})))
+	defer server.Close()
+
+	g := Generator{
+		request: versioncontrol.Client{
+			HttpClient: &http.Client{Transport: hostTransport{host: strings.TrimPrefix(server.URL, "http://")}},
+			Retry:      versioncontrol.Retry{BaseDelay: time.Millisecond},
+		},
+		SumDB: &versioncontrol.SumDB{Key: key, URL: server.URL},
+	}
+
+	gotReport, err := g.BuildReport("dep-report", []models.Dependency{
+		{Name: "example.invalid/good", Path: "example.invalid/good", Version: "v1.0.0", Sum: "h1:good=", GoModSum: "h1:goodmod="},
+		{Name: "example.invalid/tampered", Path: "example.invalid/tampered", Version: "v1.0.0", Sum: "h1:tampered=", GoModSum: "h1:goodmod="},
+		{Name: "example.invalid/unsummed", Path: "example.invalid/unsummed", Version: "v1.0.0", Warnings: []string{"example.invalid/unsummed@v1.0.0 is missing from go.sum"}},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []models.ReportObject{
+			{
+				Name:      "example.invalid/good",
+				Source:    "unknown/other",
+				Installed: models.VersionDetails{Version: "v1.0.0"},
+				Sum:       "h1:good=",
+				GoModSum:  "h1:goodmod=",
+				SumDB:     "verified",
+				Status:    "ok",
+			},
+			{
+				Name:      "example.invalid/tampered",
+				Source:    "unknown/other",
+				Installed: models.VersionDetails{Version: "v1.0.0"},
+				Sum:       "h1:tampered=",
+				GoModSum:  "h1:goodmod=",
+				SumDB:     "mismatch",
+				Warnings:  []string{"the hashes of example.invalid/tampered@v1.0.0 do not match the checksum database"},
+				Status:    "ok",
+			},
+			{
+				Name:      "example.invalid/unsummed",
+				Source:    "unknown/other",
+				Installed: models.VersionDetails{Version: "v1.0.0"},
+				SumDB:     "skipped",
+				Warnings:  []string{"example.invalid/unsummed@v1.0.0 is missing from go.sum"},
+				Status:    "ok",
+			},
+		}, gotReport.Dependencies)
+	}
+}
@@ -0,0 +1,46 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/1Password/dep-report/versioncontrol"
+)
+
+// resolvedCache holds the report objects of resolved dependencies, so that a dependency required by several
+// modules of a monorepo is only looked up once per run
+type resolvedCache struct {
+	objects sync.Map
+}
+
+// resolve reports a dependency, reusing the report object of an earlier report when the generator shares them.
+// Failures are not shared, so that every report retries them.
+func (g *Generator) resolve(ctx context.Context, request versioncontrol.Client, limiter *hostLimiter, dep models.Dependency) (*models.ReportObject, error) {
+	if g.resolved == nil {
+		return g.reportObjFromDependency(ctx, request, limiter, dep)
+	}
+
+	key := resolvedKey(dep)
+	if cached, ok := g.resolved.objects.Load(key); ok {
+		reportObject := cached.(models.ReportObject)
+		return &reportObject, nil
+	}
+
+	reportObject, err := g.reportObjFromDependency(ctx, request, limiter, dep)
+	if err != nil {
+		return nil, err
+	}
+	g.resolved.objects.Store(key, *reportObject)
+	return reportObject, nil
+}
+
+// resolvedKey identifies what is looked up for a dependency, leaving out where it appears in the module graph
+func resolvedKey(dep models.Dependency) string {
+	key := fmt.Sprintf("%s %s %s %s", dep.Name, dep.Path, dep.Version, dep.Revision)
+	if dep.Replace != nil {
+		key += " => " + resolvedKey(*dep.Replace)
+	}
+	return key
+}
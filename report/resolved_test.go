@@ -0,0 +1,86 @@
+package report
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/1Password/dep-report/versioncontrol"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingProvider resolves every dependency on example.invalid, counting the lookups
+type countingProvider struct {
+	lookups int32
+}
+
+func (p *countingProvider) Name() string { return "counting" }
+
+func (p *countingProvider) Match(host string, r versioncontrol.Client) bool {
+	return host == "example.invalid"
+}
+
+func (p *countingProvider) Website(dep models.Dependency, r versioncontrol.Client) (string, error) {
+	atomic.AddInt32(&p.lookups, 1)
+	return dep.Repo, nil
+}
+
+func (p *countingProvider) Installed(dep models.Dependency, r versioncontrol.Client) (models.VersionDetails, error) {
+	return models.VersionDetails{Version: dep.Version}, nil
+}
+
+func (p *countingProvider) Latest(dep models.Dependency, r versioncontrol.Client) (models.VersionDetails, error) {
+	return models.VersionDetails{}, nil
+}
+
+func (p *countingProvider) License(dep models.Dependency, r versioncontrol.Client) (string, error) {
+	return "MIT", nil
+}
+
+func TestResolvedCache(t *testing.T) {
+	tests := []struct {
+		description string
+		resolved    *resolvedCache
+		wantLookups int32
+	}{
+		{
+			description: "should look up dependencies shared between reports once",
+			resolved:    &resolvedCache{},
+			wantLookups: 3,
+		},
+		{
+			description: "should look up dependencies for every report without a shared cache",
+			wantLookups: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			provider := &countingProvider{}
+			g := Generator{
+				registry: versioncontrol.NewRegistry(provider),
+				resolved: test.resolved,
+			}
+
+			api, err := g.BuildReport("api", []models.Dependency{
+				{Name: "example.invalid/shared", Version: "v1.0.0"},
+				{Name: "example.invalid/api", Version: "v1.0.0"},
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			web, err := g.BuildReport("web", []models.Dependency{
+				{Name: "example.invalid/shared", Version: "v1.0.0", Indirect: true},
+				{Name: "example.invalid/web", Version: "v1.0.0"},
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, test.wantLookups, provider.lookups)
+			assert.False(t, api.Dependencies[0].Indirect)
+			assert.True(t, web.Dependencies[0].Indirect)
+			assert.Equal(t, "MIT", web.Dependencies[0].License)
+		})
+	}
+}
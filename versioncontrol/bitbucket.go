@@ -0,0 +1,280 @@
+package versioncontrol
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	bitbucketCloudHost   = "bitbucket.org"
+	bitbucketCloudAPIURL = "https://api.bitbucket.org/2.0"
+)
+
+// Bitbucket holds the settings used to call Bitbucket Cloud and Bitbucket Server
+type Bitbucket struct {
+	// ServerURL is the base URL of a self-hosted Bitbucket Server, e.g. https://bitbucket.example.com
+	ServerURL string
+	// Token is an access token sent as a bearer token to both Bitbucket Cloud and Bitbucket Server
+	Token string
+}
+
+// BitbucketFromEnv reads the Bitbucket settings from BITBUCKET_SERVER_URL and BITBUCKET_TOKEN
+func BitbucketFromEnv() Bitbucket {
+	return Bitbucket{
+		ServerURL: strings.TrimSuffix(os.Getenv("BITBUCKET_SERVER_URL"), "/"),
+		Token:     os.Getenv("BITBUCKET_TOKEN"),
+	}
+}
+
+// BitbucketProvider resolves dependencies using the 2.0 API for repositories on bitbucket.org
+// and the 1.0 REST API for Bitbucket Server
+type BitbucketProvider struct{}
+
+// ReportObjFromBitbucket uses the data in a dependency object and creates a report object from the Bitbucket API
+func ReportObjFromBitbucket(dep models.Dependency, r Client) (*models.ReportObject, error) {
+	return ReportObjFromProvider(BitbucketProvider{}, dep, r)
+}
+
+func (BitbucketProvider) Name() string {
+	return BITBUCKET
+}
+
+func (BitbucketProvider) Match(host string, r Client) bool {
+	return hostMatches(host, bitbucketCloudHost) || hostMatches(host, hostOf(r.Bitbucket.ServerURL))
+}
+
+func (BitbucketProvider) Website(dep models.Dependency, r Client) (string, error) {
+	repoURL, _, err := r.bitbucketRepoURL(dep)
+	return repoURL, err
+}
+
+func (BitbucketProvider) License(dep models.Dependency, r Client) (string, error) {
+	repoURL, cloud, err := r.bitbucketRepoURL(dep)
+	if err != nil {
+		return "", err
+	}
+
+	if !cloud {
+		// Without a ref the raw endpoint serves the default branch
+		return r.bitbucketLicense(func(fileName string) string {
+			return repoURL + "/raw/" + fileName
+		})
+	}
+
+	var repository models.BitbucketRepository
+	if err := r.getBitbucket(repoURL, &repository); err != nil {
+		return "", errors.Wrapf(err, "Unable to get from %s :", repoURL)
+	}
+	return r.bitbucketLicense(func(fileName string) string {
+		return repoURL + "/src/" + url.PathEscape(repository.MainBranch.Name) + "/" + fileName
+	})
+}
+
+func (BitbucketProvider) Installed(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	repoURL, cloud, err := r.bitbucketRepoURL(dep)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+
+	var installed models.VersionDetails
+	if cloud {
+		installed, err = r.bitbucketCloudCommit(repoURL, installedRevision(dep))
+	} else {
+		installed, err = r.bitbucketServerCommit(repoURL, installedRevision(dep))
+	}
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+
+	installed.Version = dep.Version
+	return installed, nil
+}
+
+func (BitbucketProvider) Latest(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	repoURL, cloud, err := r.bitbucketRepoURL(dep)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+
+	if cloud {
+		var repository models.BitbucketRepository
+		if err := r.getBitbucket(repoURL, &repository); err != nil {
+			return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", repoURL)
+		}
+
+		return r.bitbucketCloudCommit(repoURL, repository.MainBranch.Name)
+	}
+
+	branchURL := repoURL + "/branches/default"
+	var branch models.BitbucketServerRef
+	if err := r.getBitbucket(branchURL, &branch); err != nil {
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", branchURL)
+	}
+
+	return r.bitbucketServerCommit(repoURL, branch.LatestCommit)
+}
+
+// Tags lists the tags of the repository, following the pages of Bitbucket Cloud and Bitbucket Server
+func (BitbucketProvider) Tags(dep models.Dependency, r Client) ([]string, error) {
+	repoURL, cloud, err := r.bitbucketRepoURL(dep)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if cloud {
+		// Every page links to the next one, the last page has no link
+		tagsURL := fmt.Sprintf("%s/refs/tags?pagelen=%d", repoURL, tagsPerPage)
+		for page := 1; page <= maxTagPages && tagsURL != ""; page++ {
+			var tags models.BitbucketRefs
+			if err := r.getBitbucket(tagsURL, &tags); err != nil {
+				return nil, errors.Wrapf(err, "Unable to get from %s :", tagsURL)
+			}
+			for _, tag := range tags.Values {
+				names = append(names, tag.Name)
+			}
+			tagsURL = tags.Next
+		}
+		return names, nil
+	}
+
+	start := 0
+	for page := 1; page <= maxTagPages; page++ {
+		tagsURL := fmt.Sprintf("%s/tags?limit=%d&start=%d", repoURL, tagsPerPage, start)
+		var tags models.BitbucketServerRefs
+		if err := r.getBitbucket(tagsURL, &tags); err != nil {
+			return nil, errors.Wrapf(err, "Unable to get from %s :", tagsURL)
+		}
+		for _, tag := range tags.Values {
+			names = append(names, tag.DisplayID)
+		}
+		if tags.IsLastPage {
+			break
+		}
+		start = tags.NextPageStart
+	}
+	return names, nil
+}
+
+// bitbucketRepoURL returns the API URL of the repository hosting a dependency and whether it is on Bitbucket Cloud
+func (r *Client) bitbucketRepoURL(dep models.Dependency) (string, bool, error) {
+	repo := dep.Repo
+	if repo == "" {
+		repo = "https://" + dep.RootPath()
+	}
+
+	u, err := url.Parse(repo)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to parse repo url, %w", err)
+	}
+
+	pathElems := strings.Split(strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git"), "/")
+	if u.Host == bitbucketCloudHost {
+		if len(pathElems) < 2 {
+			return "", false, fmt.Errorf("unable to find workspace and repository in repo url %s", u)
+		}
+		return bitbucketCloudAPIURL + "/repositories/" + pathElems[0] + "/" + pathElems[1], true, nil
+	}
+
+	// Clone URLs look like /scm/{project}/{repo}.git and browse URLs like /projects/{project}/repos/{repo}
+	if len(pathElems) > 0 && pathElems[0] == "scm" {
+		pathElems = pathElems[1:]
+	}
+	if len(pathElems) >= 4 && pathElems[0] == "projects" && pathElems[2] == "repos" {
+		pathElems = []string{pathElems[1], pathElems[3]}
+	}
+	if len(pathElems) < 2 {
+		return "", false, fmt.Errorf("unable to find project and repository in repo url %s", u)
+	}
+
+	baseURL := r.Bitbucket.ServerURL
+	if baseURL == "" {
+		baseURL = "https://" + u.Host
+	}
+	return baseURL + "/rest/api/1.0/projects/" + pathElems[0] + "/repos/" + pathElems[1], false, nil
+}
+
+func (r *Client) bitbucketCloudCommit(repoURL, revision string) (models.VersionDetails, error) {
+	commitURL := repoURL + "/commit/" + url.PathEscape(revision)
+	var commit models.BitbucketCommit
+	if err := r.getBitbucket(commitURL, &commit); err != nil {
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", commitURL)
+	}
+
+	t, err := formatRFC3339Time(commit.Date)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+	return models.VersionDetails{
+		Commit: commit.Hash,
+		Time:   t,
+	}, nil
+}
+
+func (r *Client) bitbucketServerCommit(repoURL, revision string) (models.VersionDetails, error) {
+	commitURL := repoURL + "/commits/" + url.PathEscape(revision)
+	var commit models.BitbucketServerCommit
+	if err := r.getBitbucket(commitURL, &commit); err != nil {
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", commitURL)
+	}
+	return models.VersionDetails{
+		Commit: commit.ID,
+		Time:   formatBitbucketServerTime(commit.CommitterTimestamp),
+	}, nil
+}
+
+// bitbucketLicense detects the license from the first license file found in the repository,
+// as Bitbucket does not expose license metadata
+func (r *Client) bitbucketLicense(fileURL func(fileName string) string) (string, error) {
+	for _, fileName := range licenseFileNames {
+		body, err := r.getBitbucketRaw(fileURL(fileName))
+		if errors.Cause(err) == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "Unable to get %s", fileName)
+		}
+		return detectLicense(string(body)), nil
+	}
+	return "NOASSERTION", nil
+}
+
+// Bitbucket Server reports commit times as milliseconds since the epoch
+func formatBitbucketServerTime(ms int64) string {
+	return time.Unix(0, ms*int64(time.Millisecond)).UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func (r *Client) getBitbucket(url string, target interface{}) error {
+	body, err := r.getBitbucketRaw(url)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(body, target)
+	if err != nil {
+		return errors.Wrapf(err, "unable to unmarshal response body to target struct")
+	}
+
+	return nil
+}
+
+func (r *Client) getBitbucketRaw(url string) ([]byte, error) {
+	header := http.Header{}
+	if r.Bitbucket.Token != "" {
+		header.Set("Authorization", "Bearer "+r.Bitbucket.Token)
+	}
+
+	body, err := r.get(url, header)
+	if errors.Cause(err) == ErrUnauthorized {
+		return nil, errors.Wrap(err, "verify that BITBUCKET_TOKEN is set")
+	}
+	return body, err
+}
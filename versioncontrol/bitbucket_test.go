@@ -0,0 +1,100 @@
+package versioncontrol
+
+import (
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportObjFromBitbucket(t *testing.T) {
+	skipUnrecorded(t, "reportObjFromBitbucket")
+	r, c, err := SetupHTTPRecord("reportObjFromBitbucket")
+	if err != nil {
+		t.Fatalf("unable to setup http recorder, %v", err)
+	}
+	defer r.Stop()
+
+	request := Client{
+		HttpClient: c,
+	}
+
+	tests := []struct {
+		description   string
+		dependency    models.Dependency
+		wantLicense   string
+		wantWebsite   string
+		wantInstalled models.VersionDetails
+	}{
+		{
+			description: "should successfully return report object from bitbucket cloud",
+			dependency: models.Dependency{
+				Name:     "bitbucket.org/creachadair/shell",
+				Revision: "v0.0.8",
+				Version:  "v0.0.8",
+				Source:   "bitbucket",
+			},
+			wantLicense: "BSD-3-Clause",
+			wantWebsite: "https://api.bitbucket.org/2.0/repositories/creachadair/shell",
+			wantInstalled: models.VersionDetails{
+				Version: "v0.0.8",
+				Commit:  "e395e2d7c36a2a3a0e9d23cb294f07845b8d87f0",
+				Time:    "2023-12-20T01:32:19Z",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			reportObject, err := ReportObjFromBitbucket(test.dependency, request)
+			if err != nil {
+				t.Fatalf("error returned from ReportObjFromBitbucket, err: %v", err)
+			}
+			assert.Equal(t, test.wantLicense, reportObject.License)
+			assert.Equal(t, test.wantWebsite, reportObject.Website)
+			assert.Equal(t, test.wantInstalled, reportObject.Installed)
+			assertLatestSince(t, test.wantInstalled.Version, reportObject.Latest)
+		})
+	}
+}
+
+func TestBitbucketRepoURL(t *testing.T) {
+	request := Client{
+		Bitbucket: Bitbucket{ServerURL: "https://bitbucket.example.com"},
+	}
+
+	tests := []struct {
+		description string
+		dependency  models.Dependency
+		wantRepoURL string
+		wantCloud   bool
+	}{
+		{
+			description: "should use the 2.0 api for bitbucket cloud",
+			dependency:  models.Dependency{Name: "bitbucket.org/creachadair/shell/sub"},
+			wantRepoURL: "https://api.bitbucket.org/2.0/repositories/creachadair/shell",
+			wantCloud:   true,
+		},
+		{
+			description: "should read the project and repository from clone urls",
+			dependency:  models.Dependency{Name: "bitbucket.example.com/scm/dep/example.git", Repo: "https://bitbucket.example.com/scm/DEP/example.git"},
+			wantRepoURL: "https://bitbucket.example.com/rest/api/1.0/projects/DEP/repos/example",
+		},
+		{
+			description: "should read the project and repository from browse urls",
+			dependency:  models.Dependency{Name: "bitbucket.example.com/dep/example", Repo: "https://bitbucket.example.com/projects/DEP/repos/example"},
+			wantRepoURL: "https://bitbucket.example.com/rest/api/1.0/projects/DEP/repos/example",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			repoURL, cloud, err := request.bitbucketRepoURL(test.dependency)
+			if err != nil {
+				t.Fatalf("unable to get bitbucket repo url: %v", err)
+			}
+			assert.Equal(t, test.wantRepoURL, repoURL)
+			assert.Equal(t, test.wantCloud, cloud)
+		})
+	}
+}
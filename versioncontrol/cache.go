@@ -0,0 +1,154 @@
+package versioncontrol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrOffline is returned for requests that are not in the cache when the cache is used offline
+var ErrOffline = errors.New("response is not cached and the cache is offline")
+
+// Cache stores API responses on disk keyed by URL. Responses that cannot change, such as commits looked up by full SHA,
+// are served from disk without a request. Other responses are served while younger than TTL and revalidated with
+// conditional requests after that.
+type Cache struct {
+	// Dir is the directory holding the cached responses
+	Dir string
+	// TTL is how long a response that may change is served without revalidation
+	TTL time.Duration
+	// Offline serves every response from the cache, however old, and fails requests that are not cached
+	Offline bool
+}
+
+// cacheEntry is a cached response. Not found responses are cached as well,
+// so that repositories without releases are not asked for their latest release on every run.
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	Status       int       `json:"status"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	StoredAt     time.Time `json:"storedAt"`
+	Body         []byte    `json:"body,omitempty"`
+}
+
+// immutableRef matches a full or abbreviated commit SHA, or a semantic version tag with any module directory prefix
+const immutableRef = `([0-9a-f]{7,40}|([^/?&]+(/|%2F))*v[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?)`
+
+// immutableURL matches lookups of a commit by its SHA or version tag, of a single tag, of a module version on a
+// module proxy, and of records and full tiles in a checksum database
+var immutableURL = regexp.MustCompile(`(/commits?/` + immutableRef + `([/?]|$)|[?&]sha=` + immutableRef + `(&|$)|/tags/[^/?]+$|/@v/[^/?]+\.(info|mod)$|/lookup/[^?]+@[^/?]+$|/tile/[0-9]+/([0-9]+|data)/(x[0-9]{3}/)*[0-9]{3}$)`)
+
+// getCached serves a request from the cache, revalidating or fetching it when needed
+func (r *Client) getCached(url string, header http.Header) ([]byte, error) {
+	entry, cached := r.Cache.load(url)
+	if cached && (r.Cache.Offline || r.Cache.fresh(entry)) {
+		return entry.result()
+	}
+	if r.Cache.Offline {
+		return nil, errors.Wrapf(ErrOffline, "unable to get %s", url)
+	}
+
+	conditional := http.Header{}
+	for key, values := range header {
+		conditional[key] = values
+	}
+	if cached && entry.Status == http.StatusOK {
+		if entry.ETag != "" {
+			conditional.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			conditional.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := r.fetch(url, conditional)
+	switch {
+	case errors.Cause(err) == ErrNotFound:
+		r.Cache.store(cacheEntry{URL: url, Status: http.StatusNotFound, StoredAt: time.Now()})
+		return nil, err
+	case err != nil:
+		return nil, err
+	case resp.notModified && cached:
+		entry.StoredAt = time.Now()
+		r.Cache.store(entry)
+		return entry.Body, nil
+	case resp.notModified:
+		return nil, errors.Errorf("304 Not Modified returned from %s without a cached response", url)
+	}
+
+	r.Cache.store(cacheEntry{
+		URL:          url,
+		Status:       http.StatusOK,
+		ETag:         resp.header.Get("ETag"),
+		LastModified: resp.header.Get("Last-Modified"),
+		StoredAt:     time.Now(),
+		Body:         resp.body,
+	})
+	return resp.body, nil
+}
+
+// fresh reports whether a cached response can be served without revalidation
+func (c *Cache) fresh(entry cacheEntry) bool {
+	if entry.Status == http.StatusOK && immutableURL.MatchString(entry.URL) {
+		return true
+	}
+	return time.Since(entry.StoredAt) < c.TTL
+}
+
+func (c *Cache) load(url string) (cacheEntry, bool) {
+	var entry cacheEntry
+	data, err := ioutil.ReadFile(c.path(url))
+	if err != nil {
+		return entry, false
+	}
+	// A corrupt entry is treated as missing and overwritten by the next response
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return entry, false
+	}
+	return entry, true
+}
+
+// store writes an entry through a temporary file, so that concurrent readers never see a partial entry.
+// The cache is only an optimisation, so failures to write it are ignored.
+func (c *Cache) store(entry cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return
+	}
+
+	tmp, err := ioutil.TempFile(c.Dir, ".tmp-")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		return
+	}
+	os.Rename(tmp.Name(), c.path(entry.URL))
+}
+
+func (c *Cache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (entry cacheEntry) result() ([]byte, error) {
+	if entry.Status == http.StatusNotFound {
+		return nil, errors.Wrapf(ErrNotFound, "404 Not Found cached for %s", entry.URL)
+	}
+	return entry.Body, nil
+}
@@ -0,0 +1,165 @@
+package versioncontrol
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCached(t *testing.T) {
+	const sha = "614d223910a179a466c1767a985424175c39b465"
+
+	tests := []struct {
+		description      string
+		path             string
+		ttl              time.Duration
+		wantBody         string
+		wantCause        error
+		wantRequests     int
+		wantConditionals int
+	}{
+		{
+			description:  "should serve commits looked up by sha without a request",
+			path:         "/repos/pkg/errors/commits/" + sha,
+			wantBody:     "body of /repos/pkg/errors/commits/" + sha,
+			wantRequests: 1,
+		},
+		{
+			description:  "should serve mutable responses without a request while fresh",
+			path:         "/repos/pkg/errors/commits/HEAD",
+			ttl:          time.Hour,
+			wantBody:     "body of /repos/pkg/errors/commits/HEAD",
+			wantRequests: 1,
+		},
+		{
+			description:      "should revalidate stale responses with their etag",
+			path:             "/repos/pkg/errors/releases/latest",
+			wantBody:         "body of /repos/pkg/errors/releases/latest",
+			wantRequests:     2,
+			wantConditionals: 1,
+		},
+		{
+			description:  "should cache missing resources",
+			path:         "/repos/BurntSushi/toml/releases/missing",
+			ttl:          time.Hour,
+			wantCause:    ErrNotFound,
+			wantRequests: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			requests, conditionals := 0, 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				requests++
+				if req.URL.Path == "/repos/BurntSushi/toml/releases/missing" {
+					http.NotFound(w, req)
+					return
+				}
+				w.Header().Set("ETag", `"v1"`)
+				if req.Header.Get("If-None-Match") == `"v1"` {
+					conditionals++
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Write([]byte("body of " + req.URL.Path))
+			}))
+			defer server.Close()
+
+			dir, err := ioutil.TempDir("", "dep-report-cache")
+			if err != nil {
+				t.Fatalf("unable to create cache dir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			request := Client{
+				HttpClient: http.DefaultClient,
+				Cache:      &Cache{Dir: dir, TTL: test.ttl},
+			}
+
+			for i := 0; i < 2; i++ {
+				body, err := request.get(server.URL+test.path, nil)
+				if test.wantCause != nil {
+					assert.Equal(t, test.wantCause, errors.Cause(err))
+					continue
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, test.wantBody, string(body))
+			}
+			assert.Equal(t, test.wantRequests, requests)
+			assert.Equal(t, test.wantConditionals, conditionals)
+		})
+	}
+}
+
+func TestGetCachedOffline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("online"))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "dep-report-cache")
+	if err != nil {
+		t.Fatalf("unable to create cache dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	online := Client{HttpClient: http.DefaultClient, Cache: &Cache{Dir: dir}}
+	if _, err := online.get(server.URL+"/commits/HEAD", nil); err != nil {
+		t.Fatalf("unable to fill the cache: %v", err)
+	}
+	server.Close()
+
+	offline := Client{HttpClient: http.DefaultClient, Cache: &Cache{Dir: dir, Offline: true}}
+
+	body, err := offline.get(server.URL+"/commits/HEAD", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "online", string(body))
+
+	_, err = offline.get(server.URL+"/releases/latest", nil)
+	assert.Equal(t, ErrOffline, errors.Cause(err))
+}
+
+func TestImmutableURL(t *testing.T) {
+	tests := []struct {
+		url           string
+		wantImmutable bool
+	}{
+		{url: "https://api.github.com/repos/pkg/errors/commits/614d223910a179a466c1767a985424175c39b465", wantImmutable: true},
+		{url: "https://api.bitbucket.org/2.0/repositories/dep/example/commit/614d223910a179a466c1767a985424175c39b465", wantImmutable: true},
+		{url: "https://codeberg.org/api/v1/repos/dep/example/commits?limit=1&sha=614d223910a179a466c1767a985424175c39b465", wantImmutable: true},
+		{url: "https://proxy.golang.org/github.com/pkg/errors/@v/v0.9.1.info", wantImmutable: true},
+		{url: "https://gitlab.com/api/v4/projects/dep%2Fexample/repository/tags/v1.0.0", wantImmutable: true},
+		{url: "https://sum.golang.org/lookup/github.com/pkg/errors@v0.9.1", wantImmutable: true},
+		{url: "https://sum.golang.org/tile/8/0/x001/234", wantImmutable: true},
+		{url: "https://sum.golang.org/tile/8/data/x001/234", wantImmutable: true},
+		{url: "https://api.github.com/repos/pkg/errors/commits/614d223910a1", wantImmutable: true},
+		{url: "https://api.github.com/repos/pkg/errors/commits/v0.9.1", wantImmutable: true},
+		{url: "https://api.github.com/repos/googleapis/google-cloud-go/commits/storage/v1.30.1", wantImmutable: true},
+		{url: "https://gitlab.com/api/v4/projects/dep%2Fexample/repository/commits/sub%2Fv1.2.0-rc.1", wantImmutable: true},
+		{url: "https://codeberg.org/api/v1/repos/dep/example/commits?limit=1&sha=v1.0.0", wantImmutable: true},
+		{url: "https://api.github.com/repos/pkg/errors/commits/HEAD"},
+		{url: "https://api.github.com/repos/pkg/errors/commits/master"},
+		{url: "https://api.github.com/repos/pkg/errors/commits/614d22"},
+		{url: "https://api.github.com/repos/pkg/errors/commits/v1.2"},
+		{url: "https://codeberg.org/api/v1/repos/dep/example/commits?limit=1&sha=main"},
+		{url: "https://api.github.com/repos/pkg/errors/releases/latest"},
+		{url: "https://go-review.googlesource.com/projects/text/branches/master"},
+		{url: "https://go-review.googlesource.com/projects/text/tags"},
+		{url: "https://proxy.golang.org/github.com/pkg/errors/@v/list"},
+		{url: "https://sum.golang.org/tile/8/0/x001/234.p/5"},
+		{url: "https://sum.golang.org/latest"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			assert.Equal(t, test.wantImmutable, immutableURL.MatchString(test.url))
+		})
+	}
+}
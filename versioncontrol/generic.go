@@ -8,6 +8,8 @@ func ReportObjGeneric(dep models.Dependency) (*models.ReportObject, error) {
 		Source: dep.Source,
 		Installed: models.VersionDetails{
 			Version: dep.Version,
+			// Only pseudo-versions tell the commit time without a lookup
+			Time: dep.CommitTime,
 		},
 	}
 	return &reportObject, nil
@@ -2,8 +2,7 @@ package versioncontrol
 
 import (
 	"encoding/json"
-	"io/ioutil"
-	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -11,96 +10,148 @@ import (
 	"github.com/pkg/errors"
 )
 
+// goGerritHost serves the golang.org/x projects, whose Gerrit instance is go-review.googlesource.com
+const goGerritHost = "go.googlesource.com"
+
+// GerritProvider resolves the projects on go.googlesource.com and those in GerritRepoURLForPackage through the
+// Gerrit REST API. Only the go.googlesource.com projects are mirrored on GitHub, which resolves their short revisions.
+type GerritProvider struct{}
+
 // ReportObjFromGerrit uses the data in a dependency object and creates a report object
 func ReportObjFromGerrit(dep models.Dependency, r Client) (*models.ReportObject, error) {
-	var gerritRepoURL string
-	var githubRepoURL string
+	return ReportObjFromProvider(GerritProvider{}, dep, r)
+}
 
-	url, found := GerritRepoURLForPackage[dep.Name]
-	if found {
-		gerritRepoURL = url
-	}
-	url, found = GithubRepoURLForPackage[dep.Name]
-	if found {
-		githubRepoURL = url
+func (GerritProvider) Name() string {
+	return GERRIT
+}
+
+func (GerritProvider) Match(host string, r Client) bool {
+	if host == goGerritHost {
+		return true
 	}
-	if !found {
-		repoName := strings.TrimPrefix(dep.Name, "golang.org/x/")
-		gerritRepoURL = "https://go-review.googlesource.com/projects/" + repoName
-		githubRepoURL = "https://api.github.com/repos/golang/" + repoName
+	for _, repoURL := range GerritRepoURLForPackage {
+		if host == hostOf(repoURL) {
+			return true
+		}
 	}
+	return false
+}
+
+// GerritProvider always queries go-review.googlesource.com, the mapped projects and the GitHub mirrors of the go projects
+func (GerritProvider) boundToMatchedHosts() {}
+
+func (GerritProvider) Website(dep models.Dependency, r Client) (string, error) {
+	gerritRepoURL, _ := gerritRepoURLs(dep)
+	return gerritRepoURL, nil
+}
 
-	reportObject := models.ReportObject{
-		Name:    dep.Name,
-		Website: gerritRepoURL,
-		Source:  dep.Source,
+func (GerritProvider) License(dep models.Dependency, r Client) (string, error) {
+	license, ok := licenseForRepo[dep.RootPath()]
+	if !ok {
+		return "Unknown license", nil
 	}
+	return license, nil
+}
+
+func (GerritProvider) Installed(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	gerritRepoURL, githubRepoURL := gerritRepoURLs(dep)
 
 	//If the dependency comes from go.mod, we have to get the full commit SHA from github before we can call gerrit
 	//go.mod returns either semantic version (v0.3.2) or the commit SHA prefix (d3edc9973b7e)
+	revision := installedRevision(dep)
 	var githubCommit models.CommitResponse
-	if len(dep.Revision) != 40 {
-		if err := r.getGithub(githubRepoURL+"/commits/"+dep.Revision, &githubCommit); err != nil {
-			return nil, errors.Wrapf(err, "unable to get commit SHA from %s :", githubRepoURL)
+	if len(revision) != 40 {
+		if err := r.getGithub(githubRepoURL+"/commits/"+revision, &githubCommit); err != nil {
+			return models.VersionDetails{}, errors.Wrapf(err, "unable to get commit SHA from %s :", githubRepoURL)
 		}
-		dep.Revision = githubCommit.SHA
+		revision = githubCommit.SHA
 	}
 
-	commitURL := gerritRepoURL + "/commits/" + dep.Revision
+	commitURL := gerritRepoURL + "/commits/" + revision
 
 	var installed models.Commit
 	if err := r.getGerrit(commitURL, &installed); err != nil {
-		return nil, errors.Wrapf(err, "Unable to get from %s :", commitURL)
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", commitURL)
 	}
 
 	t, err := formatGerritTime(installed.Committer.Date)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Unable to formatGerritTime")
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to formatGerritTime")
 	}
-	reportObject.Installed = models.VersionDetails{
+	return models.VersionDetails{
 		Commit:  installed.CommitSHA,
 		Time:    t,
 		Version: dep.Version,
-	}
+	}, nil
+}
+
+func (GerritProvider) Latest(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	gerritRepoURL, _ := gerritRepoURLs(dep)
 
 	masterURL := gerritRepoURL + "/branches/master"
 	var masterInfo models.BranchInfo
 	if err := r.getGerrit(masterURL, &masterInfo); err != nil {
-		return nil, errors.Wrapf(err, "Unable to get from %s :", masterURL)
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", masterURL)
 	}
 
 	latestURL := gerritRepoURL + "/commits/" + masterInfo.Revision
 	var latest models.Commit
 	if err := r.getGerrit(latestURL, &latest); err != nil {
-		return nil, errors.Wrapf(err, "Unable to get from %s :", latestURL)
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", latestURL)
 	}
 
-	t, err = formatGerritTime(latest.Committer.Date)
+	t, err := formatGerritTime(latest.Committer.Date)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Unable to formatGerritTime")
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to formatGerritTime")
 	}
-	reportObject.Latest = models.VersionDetails{
+	return models.VersionDetails{
 		Commit: masterInfo.Revision,
 		Time:   t,
-	}
+	}, nil
+}
+
+// Tags lists the tags of the project, which Gerrit returns in lexical rather than version order
+func (GerritProvider) Tags(dep models.Dependency, r Client) ([]string, error) {
+	gerritRepoURL, _ := gerritRepoURLs(dep)
 
 	tagsURL := gerritRepoURL + "/tags"
 	var tags []models.Tag
 	if err := r.getGerrit(tagsURL, &tags); err != nil {
 		return nil, errors.Wrapf(err, "Unable to get from %s :", tagsURL)
 	}
-	if len(tags) > 0 {
-		lastTag := tags[len(tags)-1]
-		reportObject.Latest.Version = strings.Replace(lastTag.Ref, "refs/tags/", "", 1)
+
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = strings.TrimPrefix(tag.Ref, "refs/tags/")
 	}
+	return names, nil
+}
 
-	var ok bool
-	reportObject.License, ok = licenseForRepo[dep.Name]
-	if !ok {
-		reportObject.License = "Unknown license"
+// gerritRepoURLs returns the Gerrit project URL of a dependency and the URL of its GitHub mirror.
+// Other projects are on go.googlesource.com, named after the repo path or the golang.org/x/ path element.
+func gerritRepoURLs(dep models.Dependency) (string, string) {
+	var gerritRepoURL string
+	var githubRepoURL string
+
+	repoURL, found := GerritRepoURLForPackage[dep.RootPath()]
+	if found {
+		gerritRepoURL = repoURL
+	}
+	repoURL, found = GithubRepoURLForPackage[dep.RootPath()]
+	if found {
+		githubRepoURL = repoURL
+	}
+	if !found {
+		repoName := strings.SplitN(strings.TrimPrefix(dep.RootPath(), "golang.org/x/"), "/", 2)[0]
+		if u, err := url.Parse(dep.Repo); err == nil && u.Host == goGerritHost {
+			repoName = strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+		}
+		gerritRepoURL = "https://go-review.googlesource.com/projects/" + url.PathEscape(repoName)
+		githubRepoURL = "https://api.github.com/repos/golang/" + repoName
 	}
 
-	return &reportObject, nil
+	return gerritRepoURL, githubRepoURL
 }
 
 func formatGerritTime(t string) (string, error) {
@@ -112,17 +163,9 @@ func formatGerritTime(t string) (string, error) {
 }
 
 func (r *Client) getGerrit(url string, target interface{}) error {
-	req, err := http.NewRequest("GET", url, nil)
-
-	resp, err := r.HttpClient.Do(req)
-	if err != nil {
-		return errors.Wrapf(err, "Unable to client.Do")
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := r.get(url, nil)
 	if err != nil {
-		return errors.Wrapf(err, "Unable to ioutil.ReadAll")
+		return err
 	}
 
 	// Gerrit REST API appends a magic string before json body which needs to be removed
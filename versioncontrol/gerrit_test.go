@@ -65,7 +65,7 @@ func TestReportObjFromGerrit(t *testing.T) {
 				Latest: models.VersionDetails{
 					Commit: "35ee6fba71d7166e4e6e68d22182bb590d7e7da2",
 					Time:   "2020-04-27T12:16:02Z",
-					Version: "v0.9.0",
+					Version: "v0.56.0",
 				},
 			},
 		},
@@ -103,3 +103,45 @@ func TestReportObjFromGerrit(t *testing.T) {
 		})
 	}
 }
+
+func TestGerritRepoURLs(t *testing.T) {
+	tests := []struct {
+		description   string
+		dependency    models.Dependency
+		wantGerritURL string
+		wantGithubURL string
+	}{
+		{
+			description:   "should name the project after the repo",
+			dependency:    models.Dependency{Name: "golang.org/x/tools/gopls", Path: "golang.org/x/tools/gopls", Repo: "https://go.googlesource.com/tools"},
+			wantGerritURL: "https://go-review.googlesource.com/projects/tools",
+			wantGithubURL: "https://api.github.com/repos/golang/tools",
+		},
+		{
+			description:   "should name the project after the package without a repo",
+			dependency:    models.Dependency{Name: "golang.org/x/tools/gopls"},
+			wantGerritURL: "https://go-review.googlesource.com/projects/tools",
+			wantGithubURL: "https://api.github.com/repos/golang/tools",
+		},
+		{
+			description:   "should escape nested project names",
+			dependency:    models.Dependency{Name: "golang.org/x/example", Repo: "https://go.googlesource.com/example/nested"},
+			wantGerritURL: "https://go-review.googlesource.com/projects/example%2Fnested",
+			wantGithubURL: "https://api.github.com/repos/golang/example/nested",
+		},
+		{
+			description:   "should use the mapped project",
+			dependency:    models.Dependency{Name: "cloud.google.com/go"},
+			wantGerritURL: "https://code-review.googlesource.com/projects/gocloud",
+			wantGithubURL: "https://api.github.com/repos/googleapis/google-cloud-go",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			gerritURL, githubURL := gerritRepoURLs(test.dependency)
+			assert.Equal(t, test.wantGerritURL, gerritURL)
+			assert.Equal(t, test.wantGithubURL, githubURL)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package versioncontrol
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+)
+
+// defaultGiteaHosts are the public Gitea and Forgejo instances recognised without configuration
+var defaultGiteaHosts = []string{"codeberg.org"}
+
+// Gitea holds the settings used to call the API of Gitea, Forgejo and Codeberg instances
+type Gitea struct {
+	// Hosts are the hostnames of the instances that are routed to the Gitea provider
+	Hosts []string
+	// Token is an access token sent in the Authorization header
+	Token string
+}
+
+// GiteaFromEnv reads the Gitea settings from GITEA_HOSTS, a comma separated list of hosts added to the default ones,
+// and GITEA_TOKEN
+func GiteaFromEnv() Gitea {
+	hosts := append([]string(nil), defaultGiteaHosts...)
+	if env := os.Getenv("GITEA_HOSTS"); env != "" {
+		for _, host := range strings.Split(env, ",") {
+			if host = strings.TrimSpace(host); host != "" {
+				hosts = append(hosts, host)
+			}
+		}
+	}
+
+	return Gitea{
+		Hosts: hosts,
+		Token: os.Getenv("GITEA_TOKEN"),
+	}
+}
+
+// GiteaProvider resolves dependencies hosted on Gitea, Forgejo and Codeberg instances through the v1 API
+type GiteaProvider struct{}
+
+// ReportObjFromGitea uses the data in a dependency object and creates a report object from the Gitea API
+func ReportObjFromGitea(dep models.Dependency, r Client) (*models.ReportObject, error) {
+	return ReportObjFromProvider(GiteaProvider{}, dep, r)
+}
+
+func (GiteaProvider) Name() string {
+	return GITEA
+}
+
+func (GiteaProvider) Match(host string, r Client) bool {
+	return r.Gitea.IsGiteaHost(host)
+}
+
+func (GiteaProvider) Website(dep models.Dependency, r Client) (string, error) {
+	return giteaRepoURL(dep)
+}
+
+func (GiteaProvider) License(dep models.Dependency, r Client) (string, error) {
+	repoURL, err := giteaRepoURL(dep)
+	if err != nil {
+		return "", err
+	}
+
+	var repository models.GiteaRepository
+	if err := r.getGitea(repoURL, &repository); err != nil {
+		return "", errors.Wrapf(err, "Unable to get from %s :", repoURL)
+	}
+
+	// Only recent Gitea versions detect licenses, older ones need the license file
+	if len(repository.Licenses) > 0 {
+		return strings.Join(repository.Licenses, " AND "), nil
+	}
+
+	for _, fileName := range licenseFileNames {
+		fileURL := repoURL + "/raw/" + fileName + "?ref=" + url.QueryEscape(repository.DefaultBranch)
+		body, err := r.getGiteaRaw(fileURL)
+		if errors.Cause(err) == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "Unable to get from %s :", fileURL)
+		}
+		return detectLicense(string(body)), nil
+	}
+
+	return "NOASSERTION", nil
+}
+
+func (GiteaProvider) Installed(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	repoURL, err := giteaRepoURL(dep)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+
+	revision := installedRevision(dep)
+	installed, err := r.giteaCommit(repoURL, revision)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+	if installed == nil {
+		return models.VersionDetails{}, fmt.Errorf("no commit found for %s in %s", revision, repoURL)
+	}
+
+	installed.Version = dep.Version
+	return *installed, nil
+}
+
+func (GiteaProvider) Latest(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	repoURL, err := giteaRepoURL(dep)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+
+	var repository models.GiteaRepository
+	if err := r.getGitea(repoURL, &repository); err != nil {
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", repoURL)
+	}
+
+	var latest models.VersionDetails
+	commit, err := r.giteaCommit(repoURL, repository.DefaultBranch)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+	if commit != nil {
+		latest = *commit
+	}
+	return latest, nil
+}
+
+// Tags lists the tags of the repository page by page. Instances cap the page size at a limit of their own,
+// so pages are read until an empty one rather than a short one.
+func (GiteaProvider) Tags(dep models.Dependency, r Client) ([]string, error) {
+	repoURL, err := giteaRepoURL(dep)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for page := 1; page <= maxTagPages; page++ {
+		tagsURL := fmt.Sprintf("%s/tags?limit=%d&page=%d", repoURL, tagsPerPage, page)
+		var tags []models.GiteaTag
+		if err := r.getGitea(tagsURL, &tags); err != nil {
+			return nil, errors.Wrapf(err, "Unable to get from %s :", tagsURL)
+		}
+		if len(tags) == 0 {
+			break
+		}
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+	}
+	return names, nil
+}
+
+// giteaRepoURL returns the API URL of the repository hosting a dependency
+func giteaRepoURL(dep models.Dependency) (string, error) {
+	repo := dep.Repo
+	if repo == "" {
+		repo = "https://" + dep.RootPath()
+	}
+
+	u, err := url.Parse(repo)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse repo url, %w", err)
+	}
+	pathElems := strings.Split(strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git"), "/")
+	if len(pathElems) < 2 {
+		return "", fmt.Errorf("unable to find owner and repository in repo url %s", repo)
+	}
+
+	return "https://" + u.Host + "/api/v1/repos/" + pathElems[0] + "/" + pathElems[1], nil
+}
+
+// giteaCommit returns the most recent commit reachable from a revision, or nil when there is none.
+// The sha parameter accepts commit SHAs, SHA prefixes, branches and tags.
+func (r *Client) giteaCommit(repoURL, revision string) (*models.VersionDetails, error) {
+	commitURL := repoURL + "/commits?limit=1&sha=" + url.QueryEscape(revision)
+	var commits []models.CommitResponse
+	if err := r.getGitea(commitURL, &commits); err != nil {
+		return nil, errors.Wrapf(err, "Unable to get from %s :", commitURL)
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	t, err := formatRFC3339Time(commits[0].Commit.Committer.Date)
+	if err != nil {
+		return nil, err
+	}
+	return &models.VersionDetails{
+		Commit: commits[0].SHA,
+		Time:   t,
+	}, nil
+}
+
+// IsGiteaHost reports whether a host is one of the configured Gitea instances
+func (g Gitea) IsGiteaHost(host string) bool {
+	for _, h := range g.Hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Client) getGitea(url string, target interface{}) error {
+	body, err := r.getGiteaRaw(url)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(body, target)
+	if err != nil {
+		return errors.Wrapf(err, "unable to unmarshal response body to target struct")
+	}
+
+	return nil
+}
+
+func (r *Client) getGiteaRaw(url string) ([]byte, error) {
+	header := http.Header{}
+	if r.Gitea.Token != "" {
+		header.Set("Authorization", "token "+r.Gitea.Token)
+	}
+
+	body, err := r.get(url, header)
+	if errors.Cause(err) == ErrUnauthorized {
+		return nil, errors.Wrap(err, "verify that GITEA_TOKEN is set")
+	}
+	return body, err
+}
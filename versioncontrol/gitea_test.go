@@ -0,0 +1,73 @@
+package versioncontrol
+
+import (
+	"os"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportObjFromGitea(t *testing.T) {
+	skipUnrecorded(t, "reportObjFromGitea")
+	r, c, err := SetupHTTPRecord("reportObjFromGitea")
+	if err != nil {
+		t.Fatalf("unable to setup http recorder, %v", err)
+	}
+	defer r.Stop()
+
+	request := Client{
+		HttpClient: c,
+		Gitea:      Gitea{Hosts: defaultGiteaHosts},
+	}
+
+	tests := []struct {
+		description   string
+		dependency    models.Dependency
+		wantLicense   string
+		wantWebsite   string
+		wantInstalled models.VersionDetails
+	}{
+		{
+			description: "should successfully return report object from codeberg",
+			dependency: models.Dependency{
+				Name:     "codeberg.org/go-fonts/liberation",
+				Revision: "v0.5.0",
+				Version:  "v0.5.0",
+				Source:   "gitea",
+			},
+			wantLicense: "BSD-3-Clause",
+			wantWebsite: "https://codeberg.org/api/v1/repos/go-fonts/liberation",
+			wantInstalled: models.VersionDetails{
+				Version: "v0.5.0",
+				Commit:  "705635f45f92025d7686cd4cead31107c0788adf",
+				Time:    "2025-03-11T09:08:49Z",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			reportObject, err := ReportObjFromGitea(test.dependency, request)
+			if err != nil {
+				t.Fatalf("error returned from ReportObjFromGitea, err: %v", err)
+			}
+			// The repository also holds the SIL license of its fonts, which instances detecting licenses report as well
+			assert.Contains(t, reportObject.License, test.wantLicense)
+			assert.Equal(t, test.wantWebsite, reportObject.Website)
+			assert.Equal(t, test.wantInstalled, reportObject.Installed)
+			assertLatestSince(t, test.wantInstalled.Version, reportObject.Latest)
+		})
+	}
+}
+
+func TestGiteaFromEnv(t *testing.T) {
+	defer os.Setenv("GITEA_HOSTS", os.Getenv("GITEA_HOSTS"))
+
+	os.Setenv("GITEA_HOSTS", "")
+	assert.Equal(t, []string{"codeberg.org"}, GiteaFromEnv().Hosts)
+
+	os.Setenv("GITEA_HOSTS", "git.example.com, code.example.com")
+	assert.Equal(t, []string{"codeberg.org", "git.example.com", "code.example.com"}, GiteaFromEnv().Hosts)
+	assert.Equal(t, []string{"codeberg.org"}, defaultGiteaHosts)
+}
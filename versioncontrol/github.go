@@ -3,7 +3,6 @@ package versioncontrol
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
@@ -12,60 +11,116 @@ import (
 	"github.com/pkg/errors"
 )
 
+// GithubProvider resolves dependencies hosted on github.com through the GitHub REST API
+type GithubProvider struct{}
+
+// ReportObjFromGithub uses the data in a dependency object and creates a report object from the GitHub API
 func ReportObjFromGithub(dep models.Dependency, r Client) (*models.ReportObject, error) {
-	repoName, err := repoNameFromGithubPackage(dep.Name)
-	if err != nil {
-		return nil, err
-	}
+	return ReportObjFromProvider(GithubProvider{}, dep, r)
+}
+
+func (GithubProvider) Name() string {
+	return GITHUB
+}
+
+func (GithubProvider) Match(host string, r Client) bool {
+	return host == "github.com" || host == "api.github.com"
+}
+
+// GithubProvider always queries api.github.com
+func (GithubProvider) boundToMatchedHosts() {}
 
-	//consider whether or not we need this var
-	repoURL := "https://api.github.com/repos/" + repoName
+func (GithubProvider) Website(dep models.Dependency, r Client) (string, error) {
+	return githubRepoURL(dep)
+}
 
-	reportObject := models.ReportObject{
-		Name:    dep.Name,
-		Website: repoURL,
-		Source:  dep.Source,
+func (GithubProvider) License(dep models.Dependency, r Client) (string, error) {
+	repoURL, err := githubRepoURL(dep)
+	if err != nil {
+		return "", err
 	}
 
 	licenseURL := repoURL + "/license"
 	var licenseResponse models.LicenseResponse
-	if err := r.getGithub(licenseURL, &licenseResponse); err != nil {
-		return nil, errors.Wrapf(err, "Unable to get from %s :", licenseURL)
+	err = r.getGithub(licenseURL, &licenseResponse)
+	if errors.Cause(err) == ErrNotFound {
+		return "NOASSERTION", nil
 	}
+	if err != nil {
+		return "", errors.Wrapf(err, "Unable to get from %s :", licenseURL)
+	}
+
+	return licenseResponse.License.Name, nil
+}
 
-	reportObject.License = licenseResponse.License.Name
+func (GithubProvider) Installed(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	repoURL, err := githubRepoURL(dep)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
 
-	commitURL := repoURL + "/commits/" + dep.Revision
+	commitURL := repoURL + "/commits/" + installedRevision(dep)
 	var installed models.CommitResponse
 	if err := r.getGithub(commitURL, &installed); err != nil {
-		return nil, errors.Wrapf(err, "Unable to get from %s :", commitURL)
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", commitURL)
 	}
 
-	reportObject.Installed = models.VersionDetails{
+	return models.VersionDetails{
 		Commit:  installed.SHA,
 		Time:    installed.Commit.Committer.Date,
 		Version: dep.Version,
+	}, nil
+}
+
+func (GithubProvider) Latest(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	repoURL, err := githubRepoURL(dep)
+	if err != nil {
+		return models.VersionDetails{}, err
 	}
 
 	branchURL := repoURL + "/commits/HEAD"
 	var latest models.CommitResponse
 	if err := r.getGithub(branchURL, &latest); err != nil {
-		return nil, errors.Wrapf(err, "Unable to get from %s :", branchURL)
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", branchURL)
 	}
 
-	reportObject.Latest = models.VersionDetails{
+	return models.VersionDetails{
 		Commit: latest.SHA,
 		Time:   latest.Commit.Committer.Date,
+	}, nil
+}
+
+// Tags lists the tags of the repository page by page, since repositories that only tag have no latest release
+func (GithubProvider) Tags(dep models.Dependency, r Client) ([]string, error) {
+	repoURL, err := githubRepoURL(dep)
+	if err != nil {
+		return nil, err
 	}
 
-	releaseURL := repoURL + "/releases/latest"
-	var release models.Release
-	if err := r.getGithub(releaseURL, &release); err != nil {
-		return nil, errors.Wrapf(err, "Unable to get from %s :", releaseURL)
+	var names []string
+	for page := 1; page <= maxTagPages; page++ {
+		tagsURL := fmt.Sprintf("%s/tags?per_page=%d&page=%d", repoURL, tagsPerPage, page)
+		var tags []models.GithubTag
+		if err := r.getGithub(tagsURL, &tags); err != nil {
+			return nil, errors.Wrapf(err, "Unable to get from %s :", tagsURL)
+		}
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+		if len(tags) < tagsPerPage {
+			break
+		}
 	}
-	reportObject.Latest.Version = release.Name
+	return names, nil
+}
 
-	return &reportObject, nil
+// githubRepoURL returns the API URL of the repository hosting a dependency
+func githubRepoURL(dep models.Dependency) (string, error) {
+	repoName, err := repoNameForDependency(dep)
+	if err != nil {
+		return "", err
+	}
+	return "https://api.github.com/repos/" + repoName, nil
 }
 
 func repoNameFromGithubPackage(packageName string) (string, error) {
@@ -75,37 +130,41 @@ func repoNameFromGithubPackage(packageName string) (string, error) {
 		packageName = "https://" + packageName
 	}
 
-	u, err := url.Parse(packageName)
+	return repoNameFromURL(packageName)
+}
+
+// repoNameForDependency prefers the repo resolved for the dependency, falling back to its package name
+func repoNameForDependency(dep models.Dependency) (string, error) {
+	if _, found := GithubRepoURLForPackage[dep.RootPath()]; found || dep.Repo == "" {
+		return repoNameFromGithubPackage(dep.RootPath())
+	}
+	return repoNameFromURL(dep.Repo)
+}
+
+func repoNameFromURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
 	if err != nil {
 		return "", fmt.Errorf("unable to parse repo url, %w", err)
 	}
 	// This will cut the preceding / in the path and remove and subdirectories attached to the path.
 	// This is necessary because some of the go modules imported are imported with the subpackages in the name
 	// The repo name will then always be returned as {owner}/{project}
-	repoName := strings.Join(strings.Split(u.Path, "/")[1:3], "/")
+	pathElems := strings.Split(strings.TrimSuffix(u.Path, ".git"), "/")
+	if len(pathElems) < 3 {
+		return "", fmt.Errorf("unable to find owner and project in repo url %s", rawURL)
+	}
+	repoName := strings.Join(pathElems[1:3], "/")
 
 	return repoName, nil
 }
 
 func (r *Client) getGithub(url string, target interface{}) error {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return errors.Wrapf(err, "unable to create request for %s", url)
+	body, err := r.get(url, http.Header{"Authorization": {"token " + r.Token}})
+	if errors.Cause(err) == ErrUnauthorized {
+		return errors.Wrap(err, "verify that GITHUB_OAUTH_TOKEN is set")
 	}
-	req.Header.Add("Authorization", "token "+r.Token)
-
-	resp, err := r.HttpClient.Do(req)
-	if err != nil {
-		return errors.Wrapf(err, "unable to make http request to github")
-	}
-	if resp.StatusCode == 401 {
-		return fmt.Errorf("%s returned from github, verify that GITHUB_OAUTH_TOKEN is set", resp.Status)
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrapf(err, "unable to read response body")
+		return err
 	}
 
 	err = json.Unmarshal(body, target)
@@ -1,13 +1,38 @@
 package versioncontrol
 
 import (
+	"github.com/1Password/dep-report/internal/fixture"
 	"github.com/1Password/dep-report/models"
 	"flag"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/semver"
+	"os"
 	"testing"
 )
 
 var githubToken = flag.String("githubToken", "", "Token to be used in Github requests")
+var record = flag.Bool("record", false, "Record the cassettes missing from testData against the real APIs")
+
+// skipUnrecorded skips a test whose cassette has not been recorded yet, unless it is run with -record to record it
+func skipUnrecorded(t *testing.T, cassette string) {
+	if _, err := os.Stat("testData/" + cassette + ".yaml"); os.IsNotExist(err) && !*record {
+		t.Skipf("testData/%s.yaml is not recorded yet, run the test with -record to record it", cassette)
+	}
+}
+
+// githubTagFixtures serves the tags of the GitHub repositories in reportObjFromGithub.yaml, which was recorded before tags were
+// listed. They are no longer needed once the cassette is recorded again with -githubToken.
+var githubTagFixtures = map[string]string{
+	"https://api.github.com/repos/BurntSushi/toml/tags?per_page=100&page=1": `[{"name":"v0.3.1"},{"name":"v0.3.0"},{"name":"v0.2.0"},{"name":"v0.1.0"}]`,
+	"https://api.github.com/repos/pkg/profile/tags?per_page=100&page=1":     `[{"name":"v1.4.0"},{"name":"v1.3.0"},{"name":"v1.2.1"},{"name":"v1.2.0"},{"name":"v1.1.0"},{"name":"v1.0.0"}]`,
+}
+
+// assertLatestSince checks the latest version of a recorded repository, which is only known once it is recorded
+func assertLatestSince(t *testing.T, installed string, latest models.VersionDetails) {
+	assert.NotEmpty(t, latest.Commit)
+	assert.NotEmpty(t, latest.Time)
+	assert.True(t, semver.Compare(latest.Version, installed) >= 0, "latest version %s is older than %s", latest.Version, installed)
+}
 
 func TestRepoNameFromGithubPackage(t *testing.T) {
 	tests := []struct {
@@ -43,12 +68,24 @@ func TestRepoNameFromGithubPackage(t *testing.T) {
 	}
 }
 
+func TestRepoNameFromURL(t *testing.T) {
+	repoName, err := repoNameFromURL("https://github.com/kubernetes/client-go.git")
+	if err != nil {
+		t.Fatalf("unable to get repo name from url: %v", err)
+	}
+	assert.Equal(t, "kubernetes/client-go", repoName)
+
+	_, err = repoNameFromURL("https://github.com/kubernetes")
+	assert.Error(t, err)
+}
+
 func TestReportObjFromGithub(t *testing.T) {
 	r, c, err := SetupHTTPRecord("reportObjFromGithub")
 	if err != nil {
 		t.Fatalf("unable to setup http recorder, %v", err)
 	}
 	defer r.Stop()
+	c.Transport = fixture.Transport{Responses: githubTagFixtures, Next: c.Transport}
 
 	request := Client{
 		HttpClient: c,
@@ -61,7 +98,7 @@ func TestReportObjFromGithub(t *testing.T) {
 		wantReportObject *models.ReportObject
 	}{
 		{
-			description: "should successfully update report object where release is not available",
+			description: "should take the latest version from tags when no release is available",
 			dependency: models.Dependency{
 				Name:     "github.com/BurntSushi/toml",
 				Revision: "3012a1dbe2e4bd1391d42b32f0577cb7bbc7f005",
@@ -79,11 +116,12 @@ func TestReportObjFromGithub(t *testing.T) {
 				Latest: models.VersionDetails{
 					Commit: "3012a1dbe2e4bd1391d42b32f0577cb7bbc7f005",
 					Time:   "2018-08-15T10:47:33Z",
+					Version: "v0.3.1",
 				},
 			},
 		},
 		{
-			description: "should successfully update report object where release is available",
+			description: "should take the latest version from the highest tag",
 			dependency: models.Dependency{
 				Name:     "github.com/pkg/profile",
 				Revision: "acd64d450fd45fb2afa41f833f3788c8a7797219",
@@ -230,3 +268,35 @@ func TestGetGithub(t *testing.T) {
 		})
 	}
 }
+
+func TestGithubInstalledInSubdirectory(t *testing.T) {
+	skipUnrecorded(t, "githubInstalledInSubdirectory")
+	r, c, err := SetupHTTPRecord("githubInstalledInSubdirectory")
+	if err != nil {
+		t.Fatalf("unable to setup http recorder, %v", err)
+	}
+	defer r.Stop()
+
+	request := Client{
+		HttpClient: c,
+		Token:      *githubToken,
+	}
+
+	// The module is tagged storage/v1.30.1 in the google-cloud-go repository
+	installed, err := GithubProvider{}.Installed(models.Dependency{
+		Name:     "cloud.google.com/go/storage",
+		Path:     "cloud.google.com/go/storage",
+		Root:     "cloud.google.com/go/storage",
+		Repo:     "https://github.com/googleapis/google-cloud-go",
+		Subdir:   "storage",
+		Revision: "v1.30.1",
+		Version:  "v1.30.1",
+	}, request)
+	if assert.NoError(t, err) {
+		assert.Equal(t, models.VersionDetails{
+			Version: "v1.30.1",
+			Commit:  "c537b5101cb92da8df95f2d34334b3db54731d5c",
+			Time:    "2023-03-21T19:34:12Z",
+		}, installed)
+	}
+}
@@ -0,0 +1,233 @@
+package versioncontrol
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+)
+
+const gitlabDotCom = "https://gitlab.com"
+
+// Gitlab holds the settings used to call the GitLab v4 REST API
+type Gitlab struct {
+	// URL is the base URL of a self-hosted GitLab instance, e.g. https://gitlab.example.com
+	URL string
+	// Token is a personal access token sent in the PRIVATE-TOKEN header
+	Token string
+}
+
+// GitlabFromEnv reads the GitLab settings from GITLAB_URL and GITLAB_TOKEN
+func GitlabFromEnv() Gitlab {
+	return Gitlab{
+		URL:   strings.TrimSuffix(os.Getenv("GITLAB_URL"), "/"),
+		Token: os.Getenv("GITLAB_TOKEN"),
+	}
+}
+
+// gitlabLicenseSPDX maps GitLab license keys to the SPDX identifiers GitHub reports
+var gitlabLicenseSPDX = map[string]string{
+	"agpl-3.0":     "AGPL-3.0",
+	"apache-2.0":   "Apache-2.0",
+	"bsd-2-clause": "BSD-2-Clause",
+	"bsd-3-clause": "BSD-3-Clause",
+	"gpl-2.0":      "GPL-2.0",
+	"gpl-3.0":      "GPL-3.0",
+	"isc":          "ISC",
+	"lgpl-2.1":     "LGPL-2.1",
+	"lgpl-3.0":     "LGPL-3.0",
+	"mit":          "MIT",
+	"mpl-2.0":      "MPL-2.0",
+	"unlicense":    "Unlicense",
+}
+
+// defaultGitlabHosts are the hosts routed to the GitLab provider without configuration
+var defaultGitlabHosts = []string{"gitlab.com", "1password.io"}
+
+// GitlabProvider resolves dependencies hosted on gitlab.com or a self-hosted GitLab instance through the v4 REST API
+type GitlabProvider struct{}
+
+// ReportObjFromGitlab uses the data in a dependency object and creates a report object from the GitLab API
+func ReportObjFromGitlab(dep models.Dependency, r Client) (*models.ReportObject, error) {
+	return ReportObjFromProvider(GitlabProvider{}, dep, r)
+}
+
+func (GitlabProvider) Name() string {
+	return GITLAB
+}
+
+func (GitlabProvider) Match(host string, r Client) bool {
+	return hostMatches(host, defaultGitlabHosts...) || hostMatches(host, hostOf(r.Gitlab.URL))
+}
+
+func (GitlabProvider) Website(dep models.Dependency, r Client) (string, error) {
+	return r.gitlabProjectURL(dep)
+}
+
+func (GitlabProvider) License(dep models.Dependency, r Client) (string, error) {
+	project, err := r.gitlabProjectInfo(dep)
+	if err != nil {
+		return "", err
+	}
+	return gitlabLicense(project.License), nil
+}
+
+func (GitlabProvider) Installed(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	projectURL, err := r.gitlabProjectURL(dep)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+
+	commitURL := projectURL + "/repository/commits/" + url.PathEscape(installedRevision(dep))
+	var installed models.GitlabCommit
+	if err := r.getGitlab(commitURL, &installed); err != nil {
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", commitURL)
+	}
+
+	t, err := formatRFC3339Time(installed.CommittedDate)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+	return models.VersionDetails{
+		Commit:  installed.ID,
+		Time:    t,
+		Version: dep.Version,
+	}, nil
+}
+
+func (GitlabProvider) Latest(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	projectURL, err := r.gitlabProjectURL(dep)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+
+	// Commits are listed from the default branch, so the project does not have to be fetched again to find it
+	commitsURL := projectURL + "/repository/commits?per_page=1"
+	var commits []models.GitlabCommit
+	if err := r.getGitlab(commitsURL, &commits); err != nil {
+		return models.VersionDetails{}, errors.Wrapf(err, "Unable to get from %s :", commitsURL)
+	}
+	if len(commits) == 0 {
+		return models.VersionDetails{}, fmt.Errorf("no commits found for %s", dep.Name)
+	}
+	latest := commits[0]
+
+	t, err := formatRFC3339Time(latest.CommittedDate)
+	if err != nil {
+		return models.VersionDetails{}, err
+	}
+	return models.VersionDetails{
+		Commit: latest.ID,
+		Time:   t,
+	}, nil
+}
+
+// Tags lists the tags of the project page by page
+func (GitlabProvider) Tags(dep models.Dependency, r Client) ([]string, error) {
+	projectURL, err := r.gitlabProjectURL(dep)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for page := 1; page <= maxTagPages; page++ {
+		tagsURL := fmt.Sprintf("%s/repository/tags?per_page=%d&page=%d", projectURL, tagsPerPage, page)
+		var tags []models.GitlabTag
+		if err := r.getGitlab(tagsURL, &tags); err != nil {
+			return nil, errors.Wrapf(err, "Unable to get from %s :", tagsURL)
+		}
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+		if len(tags) < tagsPerPage {
+			break
+		}
+	}
+	return names, nil
+}
+
+// gitlabProjectURL returns the API URL of the project hosting a dependency.
+// The API accepts the URL encoded project path wherever it accepts the numeric project ID.
+func (r *Client) gitlabProjectURL(dep models.Dependency) (string, error) {
+	apiURL, projectPath, err := r.gitlabProject(dep)
+	if err != nil {
+		return "", err
+	}
+	return apiURL + "/projects/" + url.PathEscape(projectPath), nil
+}
+
+func (r *Client) gitlabProjectInfo(dep models.Dependency) (models.GitlabProject, error) {
+	var project models.GitlabProject
+	projectURL, err := r.gitlabProjectURL(dep)
+	if err != nil {
+		return project, err
+	}
+
+	if err := r.getGitlab(projectURL+"?license=true", &project); err != nil {
+		return project, errors.Wrapf(err, "Unable to get from %s :", projectURL)
+	}
+	return project, nil
+}
+
+// gitlabProject returns the API base URL and the project path for a dependency.
+// Projects on gitlab.com are always looked up there, anything else goes to the configured instance.
+func (r *Client) gitlabProject(dep models.Dependency) (string, string, error) {
+	repo := dep.Repo
+	if repo == "" {
+		repo = "https://" + dep.RootPath()
+	}
+
+	u, err := url.Parse(repo)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to parse repo url, %w", err)
+	}
+
+	projectPath := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if projectPath == "" {
+		return "", "", fmt.Errorf("unable to find project in repo url %s", repo)
+	}
+
+	baseURL := "https://" + u.Host
+	if u.Host != strings.TrimPrefix(gitlabDotCom, "https://") && r.Gitlab.URL != "" {
+		baseURL = r.Gitlab.URL
+	}
+
+	return baseURL + "/api/v4", projectPath, nil
+}
+
+func gitlabLicense(license *models.GitlabLicense) string {
+	if license == nil {
+		return "NOASSERTION"
+	}
+	if spdx, ok := gitlabLicenseSPDX[license.Key]; ok {
+		return spdx
+	}
+	return license.Name
+}
+
+func (r *Client) getGitlab(url string, target interface{}) error {
+	header := http.Header{}
+	if r.Gitlab.Token != "" {
+		header.Set("PRIVATE-TOKEN", r.Gitlab.Token)
+	}
+
+	body, err := r.get(url, header)
+	if errors.Cause(err) == ErrUnauthorized {
+		return errors.Wrap(err, "verify that GITLAB_TOKEN is set")
+	}
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(body, target)
+	if err != nil {
+		return errors.Wrapf(err, "unable to unmarshal response body to target struct")
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package versioncontrol
+
+import (
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportObjFromGitlab(t *testing.T) {
+	skipUnrecorded(t, "reportObjFromGitlab")
+	r, c, err := SetupHTTPRecord("reportObjFromGitlab")
+	if err != nil {
+		t.Fatalf("unable to setup http recorder, %v", err)
+	}
+	defer r.Stop()
+
+	request := Client{
+		HttpClient: c,
+	}
+
+	tests := []struct {
+		description   string
+		dependency    models.Dependency
+		wantLicense   string
+		wantWebsite   string
+		wantInstalled models.VersionDetails
+	}{
+		{
+			description: "should successfully return report object for a project in a nested group",
+			dependency: models.Dependency{
+				Name:     "gitlab.com/gitlab-org/api/client-go",
+				Repo:     "https://gitlab.com/gitlab-org/api/client-go",
+				Revision: "v1.8.1",
+				Version:  "v1.8.1",
+				Source:   "gitlab",
+			},
+			wantLicense: "Apache-2.0",
+			wantWebsite: "https://gitlab.com/api/v4/projects/gitlab-org%2Fapi%2Fclient-go",
+			wantInstalled: models.VersionDetails{
+				Version: "v1.8.1",
+				Commit:  "17c2b68d9d87329898c90cfd4d6e921437c58b94",
+				Time:    "2025-12-10T08:48:12Z",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			reportObject, err := ReportObjFromGitlab(test.dependency, request)
+			if err != nil {
+				t.Fatalf("error returned from ReportObjFromGitlab, err: %v", err)
+			}
+			assert.Equal(t, test.wantLicense, reportObject.License)
+			assert.Equal(t, test.wantWebsite, reportObject.Website)
+			assert.Equal(t, test.wantInstalled, reportObject.Installed)
+			assertLatestSince(t, test.wantInstalled.Version, reportObject.Latest)
+		})
+	}
+}
+
+func TestGitlabProject(t *testing.T) {
+	request := Client{
+		Gitlab: Gitlab{URL: "https://gitlab.example.com"},
+	}
+
+	tests := []struct {
+		description     string
+		dependency      models.Dependency
+		wantAPIURL      string
+		wantProjectPath string
+	}{
+		{
+			description:     "should use gitlab.com for projects hosted there",
+			dependency:      models.Dependency{Name: "gitlab.com/dep-report/example"},
+			wantAPIURL:      "https://gitlab.com/api/v4",
+			wantProjectPath: "dep-report/example",
+		},
+		{
+			description:     "should use the configured instance for other hosts",
+			dependency:      models.Dependency{Name: "1password.io/core/lib", Repo: "https://1password.io/core/lib.git"},
+			wantAPIURL:      "https://gitlab.example.com/api/v4",
+			wantProjectPath: "core/lib",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			apiURL, projectPath, err := request.gitlabProject(test.dependency)
+			if err != nil {
+				t.Fatalf("unable to get gitlab project: %v", err)
+			}
+			assert.Equal(t, test.wantAPIURL, apiURL)
+			assert.Equal(t, test.wantProjectPath, projectPath)
+		})
+	}
+}
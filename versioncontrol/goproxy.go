@@ -0,0 +1,277 @@
+package versioncontrol
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// defaultGoProxy matches the default used by the go command when GOPROXY is unset
+const defaultGoProxy = "https://proxy.golang.org,direct"
+
+// ErrNoProxy is returned when none of the configured proxies is able to serve a module,
+// either because the module is private, proxying is turned off or every proxy reported it as not found
+var ErrNoProxy = errors.New("no module proxy available")
+
+// GoProxy holds the configuration used to query module proxies speaking the GOPROXY protocol
+// https://go.dev/ref/mod#goproxy-protocol
+type GoProxy struct {
+	// Proxy is a list of proxy URLs in GOPROXY format, e.g. "https://proxy.golang.org,direct"
+	Proxy string
+	// NoProxy is a comma separated list of module path prefix patterns that must not be fetched through a proxy
+	NoProxy string
+}
+
+// GoProxyFromEnv builds a GoProxy from the GOPROXY, GONOPROXY and GOPRIVATE environment variables,
+// following the same defaults as the go command
+func GoProxyFromEnv() GoProxy {
+	proxy := os.Getenv("GOPROXY")
+	if proxy == "" {
+		proxy = defaultGoProxy
+	}
+
+	noProxy := os.Getenv("GONOPROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("GOPRIVATE")
+	}
+
+	return GoProxy{
+		Proxy:   proxy,
+		NoProxy: noProxy,
+	}
+}
+
+type proxySpec struct {
+	url string
+	// fallBackOnError is set when the proxy was followed by a pipe in GOPROXY,
+	// meaning that any error moves on to the next proxy rather than only 404 and 410
+	fallBackOnError bool
+}
+
+// proxiesFor returns the proxies that may be used to look up modPath, in order.
+// "direct" and "off" end the list because dep-report never talks to the VCS behind a module on its own.
+func (p GoProxy) proxiesFor(modPath string) []proxySpec {
+	if module.MatchPrefixPatterns(p.NoProxy, modPath) {
+		return nil
+	}
+
+	var proxies []proxySpec
+	for list := p.Proxy; list != ""; {
+		var entry string
+		fallBackOnError := false
+		if i := strings.IndexAny(list, ",|"); i >= 0 {
+			entry = list[:i]
+			fallBackOnError = list[i] == '|'
+			list = list[i+1:]
+		} else {
+			entry = list
+			list = ""
+		}
+
+		entry = strings.TrimSpace(entry)
+		switch entry {
+		case "":
+			continue
+		case "direct", "off":
+			return proxies
+		}
+
+		proxies = append(proxies, proxySpec{
+			url:             strings.TrimSuffix(entry, "/"),
+			fallBackOnError: fallBackOnError,
+		})
+	}
+	return proxies
+}
+
+// Host returns the host of the first proxy that may be asked for modPath, the one contacted unless it does not serve
+// the module, or an empty string when no proxy may be used
+func (p GoProxy) Host(modPath string) string {
+	proxies := p.proxiesFor(modPath)
+	if len(proxies) == 0 {
+		return ""
+	}
+	return hostOf(proxies[0].url)
+}
+
+// ReportObjFromGoProxy creates a report object by asking the module proxy for the installed and latest versions of a module
+func ReportObjFromGoProxy(dep models.Dependency, r Client) (*models.ReportObject, error) {
+	if dep.Path == "" || dep.Version == "" {
+		return nil, errors.Wrapf(ErrNoProxy, "%s has no module version", dep.Name)
+	}
+	version := IncompatibleVersion(dep.Path, dep.Version)
+
+	reportObject := models.ReportObject{
+		Name:    dep.Name,
+		Source:  dep.Source,
+		Website: "https://pkg.go.dev/" + dep.Path,
+	}
+
+	installed, err := r.ModuleInfo(dep.Path, version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get info for %s@%s", dep.Path, version)
+	}
+	reportObject.Installed, err = versionDetailsFromModuleInfo(*installed)
+	if err != nil {
+		return nil, err
+	}
+	reportObject.Installed.Version = dep.Version
+
+	latest, err := r.latestModuleInfo(dep.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get latest version of %s", dep.Path)
+	}
+	reportObject.Latest, err = versionDetailsFromModuleInfo(*latest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reportObject, nil
+}
+
+// ModuleVersions returns the list of tagged versions of a module known to the proxy
+func (r *Client) ModuleVersions(modPath string) ([]string, error) {
+	body, err := r.getProxy(modPath, "/@v/list")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(body)), nil
+}
+
+// ModuleLatest returns the proxy's idea of the latest version of a module using the optional @latest endpoint
+func (r *Client) ModuleLatest(modPath string) (*models.ModuleInfo, error) {
+	var info models.ModuleInfo
+	if err := r.getProxyJSON(modPath, "/@latest", &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ModuleInfo returns the version and commit time of a single module version
+func (r *Client) ModuleInfo(modPath, version string) (*models.ModuleInfo, error) {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to escape version %s", version)
+	}
+
+	var info models.ModuleInfo
+	if err := r.getProxyJSON(modPath, "/@v/"+escapedVersion+".info", &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ModuleGoMod returns the go.mod file of a single module version
+func (r *Client) ModuleGoMod(modPath, version string) ([]byte, error) {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to escape version %s", version)
+	}
+	return r.getProxy(modPath, "/@v/"+escapedVersion+".mod")
+}
+
+// latestModuleInfo returns the info of the latest version in the version list,
+// and only uses the @latest endpoint when no versions are tagged
+func (r *Client) latestModuleInfo(modPath string) (*models.ModuleInfo, error) {
+	versions, err := r.ModuleVersions(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := latestVersion(versions)
+	if latest == "" {
+		return r.ModuleLatest(modPath)
+	}
+	return r.ModuleInfo(modPath, latest)
+}
+
+// latestVersion mirrors the go command: the highest release wins, then the highest prerelease
+func latestVersion(versions []string) string {
+	latest := ""
+	for _, v := range versions {
+		if !semver.IsValid(v) {
+			continue
+		}
+		switch {
+		case latest == "":
+			latest = v
+		case semver.Prerelease(latest) != "" && semver.Prerelease(v) == "":
+			latest = v
+		case (semver.Prerelease(latest) == "") == (semver.Prerelease(v) == "") && semver.Compare(v, latest) > 0:
+			latest = v
+		}
+	}
+	return latest
+}
+
+func (r *Client) getProxyJSON(modPath, suffix string, target interface{}) error {
+	body, err := r.getProxy(modPath, suffix)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, target); err != nil {
+		return errors.Wrapf(err, "unable to unmarshal response body to target struct")
+	}
+	return nil
+}
+
+// getProxy walks the configured proxies in order until one of them serves the request
+func (r *Client) getProxy(modPath, suffix string) ([]byte, error) {
+	escapedPath, err := module.EscapePath(modPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to escape module path %s", modPath)
+	}
+
+	for _, proxy := range r.GoProxy.proxiesFor(modPath) {
+		url := proxy.url + "/" + escapedPath + suffix
+
+		body, err := r.get(url, nil)
+		switch {
+		case err == nil:
+			return body, nil
+		case errors.Cause(err) == ErrNotFound || proxy.fallBackOnError:
+			continue
+		default:
+			return nil, err
+		}
+	}
+
+	return nil, errors.Wrapf(ErrNoProxy, "unable to get %s%s", modPath, suffix)
+}
+
+// IncompatibleVersion restores the +incompatible suffix trimmed by the go.mod parser, which v2 and later
+// versions of a module without a major version suffix carry and which the proxy needs to find them
+func IncompatibleVersion(modPath, version string) string {
+	_, pathMajor, ok := module.SplitPathVersion(modPath)
+	major := semver.Major(version)
+	if ok && pathMajor == "" && major != "" && major != "v0" && major != "v1" && semver.Build(version) == "" {
+		return version + "+incompatible"
+	}
+	return version
+}
+
+func versionDetailsFromModuleInfo(info models.ModuleInfo) (models.VersionDetails, error) {
+	details := models.VersionDetails{
+		Version: info.Version,
+	}
+
+	if info.Time != "" {
+		t, err := formatRFC3339Time(info.Time)
+		if err != nil {
+			return details, err
+		}
+		details.Time = t
+	}
+
+	if info.Origin != nil {
+		details.Commit = info.Origin.Hash
+	}
+
+	return details, nil
+}
@@ -0,0 +1,223 @@
+package versioncontrol
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestProxy serves a fixed set of responses keyed by request path. A key with a query only matches requests with
+// exactly that query, which takes precedence over the key of the path alone.
+func newTestProxy(responses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, ok := responses[req.URL.Path+"?"+req.URL.RawQuery]
+		if !ok {
+			body, ok = responses[req.URL.Path]
+		}
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func TestReportObjFromGoProxy(t *testing.T) {
+	proxy := newTestProxy(map[string]string{
+		"/example.com/!burnt!sushi/mod/@v/list":                            "v1.0.0\nv1.1.0\nv1.2.0-rc.1\n",
+		"/example.com/!burnt!sushi/mod/@v/v1.0.0.info":                     `{"Version":"v1.0.0","Time":"2019-04-25T21:42:06Z"}`,
+		"/example.com/!burnt!sushi/mod/@v/v1.1.0.info":                     `{"Version":"v1.1.0","Time":"2020-03-06T10:41:05-05:00","Origin":{"VCS":"git","URL":"https://example.com/mod","Hash":"06d492aade888ab8698aad35476286b7b555c961"}}`,
+		"/example.com/untagged/@v/list":                                    "",
+		"/example.com/untagged/@v/v0.0.0-20200101000000-abcdefabcdef.info": `{"Version":"v0.0.0-20200101000000-abcdefabcdef","Time":"2020-01-01T00:00:00Z"}`,
+		"/example.com/untagged/@latest":                                    `{"Version":"v0.0.0-20200202000000-fedcbafedcba","Time":"2020-02-02T00:00:00Z"}`,
+		"/example.com/legacy/@v/list":                                      "v2.0.0+incompatible\n",
+		"/example.com/legacy/@v/v2.0.0+incompatible.info":                  `{"Version":"v2.0.0+incompatible","Time":"2018-01-01T00:00:00Z"}`,
+	})
+	defer proxy.Close()
+
+	request := Client{
+		HttpClient: proxy.Client(),
+		GoProxy:    GoProxy{Proxy: proxy.URL},
+	}
+
+	tests := []struct {
+		description      string
+		dependency       models.Dependency
+		wantReportObject *models.ReportObject
+	}{
+		{
+			description: "should report installed and latest release, ignoring prereleases",
+			dependency: models.Dependency{
+				Name:    "example.com/BurntSushi/mod",
+				Path:    "example.com/BurntSushi/mod",
+				Version: "v1.0.0",
+				Source:  "goproxy",
+			},
+			wantReportObject: &models.ReportObject{
+				Name:    "example.com/BurntSushi/mod",
+				Source:  "goproxy",
+				Website: "https://pkg.go.dev/example.com/BurntSushi/mod",
+				Installed: models.VersionDetails{
+					Version: "v1.0.0",
+					Time:    "2019-04-25T21:42:06Z",
+				},
+				Latest: models.VersionDetails{
+					Version: "v1.1.0",
+					Time:    "2020-03-06T15:41:05Z",
+					Commit:  "06d492aade888ab8698aad35476286b7b555c961",
+				},
+			},
+		},
+		{
+			description: "should fall back to @latest when no versions are tagged",
+			dependency: models.Dependency{
+				Name:    "example.com/untagged",
+				Path:    "example.com/untagged",
+				Version: "v0.0.0-20200101000000-abcdefabcdef",
+				Source:  "goproxy",
+			},
+			wantReportObject: &models.ReportObject{
+				Name:    "example.com/untagged",
+				Source:  "goproxy",
+				Website: "https://pkg.go.dev/example.com/untagged",
+				Installed: models.VersionDetails{
+					Version: "v0.0.0-20200101000000-abcdefabcdef",
+					Time:    "2020-01-01T00:00:00Z",
+				},
+				Latest: models.VersionDetails{
+					Version: "v0.0.0-20200202000000-fedcbafedcba",
+					Time:    "2020-02-02T00:00:00Z",
+				},
+			},
+		},
+		{
+			description: "should restore the incompatible flag trimmed from the version",
+			dependency: models.Dependency{
+				Name:    "example.com/legacy",
+				Path:    "example.com/legacy",
+				Version: "v2.0.0",
+				Source:  "goproxy",
+			},
+			wantReportObject: &models.ReportObject{
+				Name:    "example.com/legacy",
+				Source:  "goproxy",
+				Website: "https://pkg.go.dev/example.com/legacy",
+				Installed: models.VersionDetails{
+					Version: "v2.0.0",
+					Time:    "2018-01-01T00:00:00Z",
+				},
+				Latest: models.VersionDetails{
+					Version: "v2.0.0+incompatible",
+					Time:    "2018-01-01T00:00:00Z",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			reportObject, err := ReportObjFromGoProxy(test.dependency, request)
+			if err != nil {
+				t.Errorf("error returned from ReportObjFromGoProxy, err: %v", err)
+			}
+			assert.EqualValues(t, test.wantReportObject, reportObject)
+		})
+	}
+}
+
+func TestGetProxy(t *testing.T) {
+	good := newTestProxy(map[string]string{
+		"/example.com/mod/@v/list": "v1.0.0\n",
+	})
+	defer good.Close()
+
+	missing := newTestProxy(map[string]string{})
+	defer missing.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	tests := []struct {
+		description  string
+		goProxy      GoProxy
+		wantVersions []string
+		wantNoProxy  bool
+		wantError    bool
+	}{
+		{
+			description:  "should fall back to the next proxy after a 404 when separated by a comma",
+			goProxy:      GoProxy{Proxy: missing.URL + "," + good.URL},
+			wantVersions: []string{"v1.0.0"},
+		},
+		{
+			description: "should stop on other errors when separated by a comma",
+			goProxy:     GoProxy{Proxy: broken.URL + "," + good.URL},
+			wantError:   true,
+		},
+		{
+			description:  "should fall back to the next proxy after any error when separated by a pipe",
+			goProxy:      GoProxy{Proxy: broken.URL + "|" + good.URL},
+			wantVersions: []string{"v1.0.0"},
+		},
+		{
+			description: "should not use proxies after direct",
+			goProxy:     GoProxy{Proxy: missing.URL + ",direct," + good.URL},
+			wantNoProxy: true,
+		},
+		{
+			description: "should not use proxies when turned off",
+			goProxy:     GoProxy{Proxy: "off"},
+			wantNoProxy: true,
+		},
+		{
+			description: "should not use proxies for modules matching GONOPROXY or GOPRIVATE",
+			goProxy:     GoProxy{Proxy: good.URL, NoProxy: "*.corp.example,example.com"},
+			wantNoProxy: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			request := Client{
+				HttpClient: http.DefaultClient,
+				GoProxy:    test.goProxy,
+				Retry:      Retry{BaseDelay: time.Millisecond},
+			}
+
+			versions, err := request.ModuleVersions("example.com/mod")
+			switch {
+			case test.wantNoProxy:
+				assert.Equal(t, ErrNoProxy, errors.Cause(err))
+			case test.wantError:
+				assert.Error(t, err)
+				assert.NotEqual(t, ErrNoProxy, errors.Cause(err))
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, test.wantVersions, versions)
+			}
+		})
+	}
+}
+
+func TestGoProxyHost(t *testing.T) {
+	proxy := GoProxy{Proxy: "https://Proxy.example.com/mod|https://proxy.golang.org,direct", NoProxy: "example.com/private"}
+
+	assert.Equal(t, "Proxy.example.com", proxy.Host("github.com/pkg/errors"))
+	assert.Equal(t, "", proxy.Host("example.com/private/lib"))
+	assert.Equal(t, "", GoProxy{Proxy: "direct"}.Host("github.com/pkg/errors"))
+}
+
+func TestIncompatibleVersion(t *testing.T) {
+	assert.Equal(t, "v2.0.0+incompatible", IncompatibleVersion("github.com/foo/bar", "v2.0.0"))
+	assert.Equal(t, "v2.0.0", IncompatibleVersion("github.com/foo/bar/v2", "v2.0.0"))
+	assert.Equal(t, "v1.2.0", IncompatibleVersion("github.com/foo/bar", "v1.2.0"))
+	assert.Equal(t, "v2.0.0", IncompatibleVersion("gopkg.in/yaml.v2", "v2.0.0"))
+	assert.Equal(t, "master", IncompatibleVersion("github.com/foo/bar", "master"))
+}
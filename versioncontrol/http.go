@@ -0,0 +1,207 @@
+package versioncontrol
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Errors returned by the requests of a Client, which callers can check with errors.Cause
+var (
+	ErrRateLimited  = errors.New("rate limit exceeded")
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+const (
+	defaultMaxAttempts = 4
+	defaultBaseDelay   = 500 * time.Millisecond
+	defaultMaxWait     = time.Minute
+)
+
+// Retry configures how a Client retries failed requests. Zero values select the defaults.
+type Retry struct {
+	// MaxAttempts is the number of times a request is sent before giving up, defaulting to 4
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled for every further retry, defaulting to 500ms
+	BaseDelay time.Duration
+	// MaxWait is the longest the client waits for a rate limit to reset, defaulting to one minute
+	MaxWait time.Duration
+}
+
+// noRetry marks responses that retrying cannot fix
+const noRetry = time.Duration(-1)
+
+// response is a successful response, or a 304 when revalidating a cached one
+type response struct {
+	body        []byte
+	header      http.Header
+	notModified bool
+}
+
+// get sends a GET request with the given headers and returns the body of a 200 response,
+// going through the cache when the client has one
+func (r *Client) get(url string, header http.Header) ([]byte, error) {
+	if r.Cache != nil {
+		return r.getCached(url, header)
+	}
+
+	resp, err := r.fetch(url, header)
+	if err != nil {
+		return nil, err
+	}
+	return resp.body, nil
+}
+
+// fetch sends a GET request with the given headers. Network failures and server errors are retried with exponential
+// backoff and jitter, and rate limited requests are retried once the limit resets, unless that takes longer than
+// Retry.MaxWait.
+func (r *Client) fetch(url string, header http.Header) (*response, error) {
+	maxAttempts := r.Retry.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	maxWait := r.Retry.MaxWait
+	if maxWait <= 0 {
+		maxWait = defaultMaxWait
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, wait, err := r.getOnce(url, header)
+		if err == nil {
+			return resp, nil
+		}
+		if wait == noRetry || attempt == maxAttempts {
+			return nil, err
+		}
+
+		if wait == 0 {
+			wait = r.backoff(attempt)
+		}
+		if wait > maxWait {
+			return nil, errors.Wrapf(err, "retrying in %s would exceed the wait limit of %s", wait, maxWait)
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-r.context().Done():
+			return nil, errors.Wrapf(r.context().Err(), "gave up on %s", url)
+		}
+	}
+}
+
+// getOnce sends a single request. Along with any error it returns how long to wait before retrying,
+// zero to back off exponentially or noRetry when the request should not be retried.
+func (r *Client) getOnce(url string, header http.Header) (*response, time.Duration, error) {
+	req, err := r.newRequest(url)
+	if err != nil {
+		return nil, noRetry, errors.Wrapf(err, "unable to create request for %s", url)
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := r.HttpClient.Do(req)
+	if err != nil {
+		if r.context().Err() != nil || unreachable(err) {
+			return nil, noRetry, errors.Wrapf(err, "unable to make http request to %s", url)
+		}
+		return nil, 0, errors.Wrapf(err, "unable to make http request to %s", url)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "unable to read response body")
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return &response{body: body, header: resp.Header}, 0, nil
+	case resp.StatusCode == http.StatusNotModified:
+		return &response{header: resp.Header, notModified: true}, 0, nil
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, noRetry, errors.Wrapf(ErrUnauthorized, "%s returned from %s", resp.Status, url)
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusGone:
+		return nil, noRetry, errors.Wrapf(ErrNotFound, "%s returned from %s", resp.Status, url)
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusForbidden:
+		wait, limited := rateLimitWait(resp.Header, time.Now())
+		// GitHub reports secondary rate limits as a 403 that may not carry any headers
+		if !limited && resp.StatusCode == http.StatusForbidden && !strings.Contains(strings.ToLower(string(body)), "rate limit") {
+			return nil, noRetry, errors.Wrapf(ErrUnauthorized, "%s returned from %s", resp.Status, url)
+		}
+		return nil, wait, errors.Wrapf(ErrRateLimited, "%s returned from %s", resp.Status, url)
+	case resp.StatusCode >= 500:
+		return nil, 0, fmt.Errorf("%s returned from %s", resp.Status, url)
+	default:
+		return nil, noRetry, fmt.Errorf("%s returned from %s", resp.Status, url)
+	}
+}
+
+// unreachable reports whether a request failed because its host does not resolve or refuses connections, which
+// retrying will not fix. The go-get lookups of import path prefixes fail like this for most hosts they try.
+func unreachable(err error) bool {
+	err = errors.Cause(err)
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) {
+		return !dnsErr.IsTemporary && !dnsErr.IsTimeout
+	}
+	return stderrors.Is(err, syscall.ECONNREFUSED)
+}
+
+// rateLimitWait reads how long to wait for a rate limit from Retry-After, or from the reset time GitHub sends in
+// X-RateLimit-Reset and GitLab in RateLimit-Reset once no requests remain
+func rateLimitWait(header http.Header, now time.Time) (time.Duration, bool) {
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if t, err := http.ParseTime(retryAfter); err == nil {
+			return positive(t.Sub(now)), true
+		}
+	}
+
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		if header.Get(prefix+"Remaining") != "0" {
+			continue
+		}
+		reset, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64)
+		if err != nil {
+			return 0, true
+		}
+		return positive(time.Unix(reset, 0).Sub(now)), true
+	}
+
+	return 0, false
+}
+
+// backoff returns the delay before a retry, doubling with every attempt and jittered so that
+// concurrent requests do not retry in lockstep
+func (r *Client) backoff(attempt int) time.Duration {
+	base := r.Retry.BaseDelay
+	if base <= 0 {
+		base = defaultBaseDelay
+	}
+
+	delay := base << uint(attempt-1)
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// positive rounds negative durations, from clocks out of sync, up to zero
+func positive(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,175 @@
+package versioncontrol
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testResponse struct {
+	status int
+	header map[string]string
+	body   string
+}
+
+func TestGet(t *testing.T) {
+	farFuture := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	tests := []struct {
+		description  string
+		responses    []testResponse
+		wantBody     string
+		wantCause    error
+		wantError    bool
+		wantAttempts int
+	}{
+		{
+			description:  "should return the body of a successful response",
+			responses:    []testResponse{{status: 200, body: "ok"}},
+			wantBody:     "ok",
+			wantAttempts: 1,
+		},
+		{
+			description:  "should retry server errors",
+			responses:    []testResponse{{status: 502}, {status: 503}, {status: 200, body: "ok"}},
+			wantBody:     "ok",
+			wantAttempts: 3,
+		},
+		{
+			description:  "should give up after the maximum number of attempts",
+			responses:    []testResponse{{status: 500}, {status: 500}, {status: 500}, {status: 500}, {status: 200}},
+			wantError:    true,
+			wantAttempts: 4,
+		},
+		{
+			description:  "should not retry missing resources",
+			responses:    []testResponse{{status: 404}, {status: 200}},
+			wantCause:    ErrNotFound,
+			wantAttempts: 1,
+		},
+		{
+			description:  "should not retry unauthorized requests",
+			responses:    []testResponse{{status: 401}, {status: 200}},
+			wantCause:    ErrUnauthorized,
+			wantAttempts: 1,
+		},
+		{
+			description:  "should treat forbidden requests without rate limit details as unauthorized",
+			responses:    []testResponse{{status: 403, body: "Resource not accessible by integration"}, {status: 200}},
+			wantCause:    ErrUnauthorized,
+			wantAttempts: 1,
+		},
+		{
+			description:  "should wait for Retry-After",
+			responses:    []testResponse{{status: 429, header: map[string]string{"Retry-After": "0"}}, {status: 200, body: "ok"}},
+			wantBody:     "ok",
+			wantAttempts: 2,
+		},
+		{
+			description:  "should back off from secondary rate limits",
+			responses:    []testResponse{{status: 403, body: `{"message":"You have exceeded a secondary rate limit"}`}, {status: 200, body: "ok"}},
+			wantBody:     "ok",
+			wantAttempts: 2,
+		},
+		{
+			description: "should not wait for a rate limit reset beyond the wait limit",
+			responses: []testResponse{
+				{status: 403, header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": farFuture}},
+				{status: 200},
+			},
+			wantCause:    ErrRateLimited,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				response := test.responses[attempts]
+				attempts++
+				for key, value := range response.header {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(response.status)
+				w.Write([]byte(response.body))
+			}))
+			defer server.Close()
+
+			request := Client{
+				HttpClient: http.DefaultClient,
+				Retry:      Retry{BaseDelay: time.Millisecond},
+			}
+
+			body, err := request.get(server.URL, nil)
+			switch {
+			case test.wantCause != nil:
+				assert.Equal(t, test.wantCause, errors.Cause(err))
+			case test.wantError:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, test.wantBody, string(body))
+			}
+			assert.Equal(t, test.wantAttempts, attempts)
+		})
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	tests := []struct {
+		description string
+		header      http.Header
+		wantWait    time.Duration
+		wantLimited bool
+	}{
+		{
+			description: "should read Retry-After seconds",
+			header:      http.Header{"Retry-After": {"30"}},
+			wantWait:    30 * time.Second,
+			wantLimited: true,
+		},
+		{
+			description: "should read Retry-After dates",
+			header:      http.Header{"Retry-After": {now.Add(time.Minute).UTC().Format(http.TimeFormat)}},
+			wantWait:    time.Minute,
+			wantLimited: true,
+		},
+		{
+			description: "should wait for the github rate limit reset",
+			header:      http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1600000090"}},
+			wantWait:    90 * time.Second,
+			wantLimited: true,
+		},
+		{
+			description: "should wait for the gitlab rate limit reset",
+			header:      http.Header{"Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"1600000010"}},
+			wantWait:    10 * time.Second,
+			wantLimited: true,
+		},
+		{
+			description: "should not wait for resets in the past",
+			header:      http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1599999990"}},
+			wantLimited: true,
+		},
+		{
+			description: "should not report a rate limit while requests remain",
+			header:      http.Header{"X-Ratelimit-Remaining": {"12"}, "X-Ratelimit-Reset": {"1600000090"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			wait, limited := rateLimitWait(test.header, now)
+			assert.Equal(t, test.wantLimited, limited)
+			assert.Equal(t, test.wantWait, wait)
+		})
+	}
+}
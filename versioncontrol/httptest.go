@@ -16,6 +16,7 @@ func SetupHTTPRecord(fileName string) (*recorder.Recorder, *http.Client, error){
 
 	r.AddFilter(func(i *cassette.Interaction) error {
 		delete(i.Request.Headers, "Authorization")
+		delete(i.Request.Headers, "Private-Token")
 		return nil
 	})
 
@@ -0,0 +1,47 @@
+package versioncontrol
+
+import (
+	"regexp"
+	"strings"
+)
+
+// licenseFileNames are the files checked, in order, when a provider has no license metadata
+var licenseFileNames = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "COPYING"}
+
+var whitespaceRegex = regexp.MustCompile(`\s+`)
+
+// detectLicense identifies the most common open source licenses from the text of a license file.
+// It returns the SPDX identifier of the license, or NOASSERTION like GitHub does when the license is not recognised.
+func detectLicense(text string) string {
+	t := strings.ToLower(whitespaceRegex.ReplaceAllString(text, " "))
+
+	switch {
+	case strings.Contains(t, "apache license") && strings.Contains(t, "version 2.0"):
+		return "Apache-2.0"
+	case strings.Contains(t, "mozilla public license") && strings.Contains(t, "version 2.0"):
+		return "MPL-2.0"
+	case strings.Contains(t, "gnu affero general public license"):
+		return "AGPL-3.0"
+	case strings.Contains(t, "gnu lesser general public license") && strings.Contains(t, "version 3"):
+		return "LGPL-3.0"
+	case strings.Contains(t, "gnu lesser general public license"):
+		return "LGPL-2.1"
+	case strings.Contains(t, "gnu general public license") && strings.Contains(t, "version 3"):
+		return "GPL-3.0"
+	case strings.Contains(t, "gnu general public license") && strings.Contains(t, "version 2"):
+		return "GPL-2.0"
+	case strings.Contains(t, "permission is hereby granted, free of charge"):
+		return "MIT"
+	case strings.Contains(t, "permission to use, copy, modify, and/or distribute this software"):
+		return "ISC"
+	case strings.Contains(t, "redistribution and use in source and binary forms"):
+		if strings.Contains(t, "neither the name") || strings.Contains(t, "names of its contributors") {
+			return "BSD-3-Clause"
+		}
+		return "BSD-2-Clause"
+	case strings.Contains(t, "this is free and unencumbered software released into the public domain"):
+		return "Unlicense"
+	default:
+		return "NOASSERTION"
+	}
+}
@@ -0,0 +1,47 @@
+package versioncontrol
+
+import (
+	"testing"
+)
+
+func TestDetectLicense(t *testing.T) {
+	tests := []struct {
+		description string
+		text        string
+		wantLicense string
+	}{
+		{
+			description: "should detect MIT regardless of line wrapping",
+			text:        "MIT License\n\nPermission is hereby granted,\nfree of charge, to any person obtaining a copy",
+			wantLicense: "MIT",
+		},
+		{
+			description: "should detect Apache 2.0",
+			text:        "                                 Apache License\n                           Version 2.0, January 2004",
+			wantLicense: "Apache-2.0",
+		},
+		{
+			description: "should detect BSD-3-Clause by its non-endorsement clause",
+			text:        "Redistribution and use in source and binary forms, with or without modification...\n* Neither the name of Google Inc. nor the names of its contributors may be used",
+			wantLicense: "BSD-3-Clause",
+		},
+		{
+			description: "should detect BSD-2-Clause",
+			text:        "Redistribution and use in source and binary forms, with or without modification, are permitted",
+			wantLicense: "BSD-2-Clause",
+		},
+		{
+			description: "should report unrecognised licenses as NOASSERTION",
+			text:        "All rights reserved.",
+			wantLicense: "NOASSERTION",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if got := detectLicense(test.text); got != test.wantLicense {
+				t.Errorf("license did not match expected license, want: %s, got: %s", test.wantLicense, got)
+			}
+		})
+	}
+}
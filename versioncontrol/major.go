@@ -0,0 +1,162 @@
+package versioncontrol
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// maxMajorProbes bounds how many major versions past the installed one are looked for
+const maxMajorProbes = 100
+
+// MajorVersions holds what the major version line of an installed module and its newer major versions offer
+type MajorVersions struct {
+	// Latest is the latest version with the major version of the installed one, with an empty Version when there is none
+	Latest models.VersionDetails
+	// Newest is the latest version of the newest major version, with an empty Path when the installed major is the newest
+	Newest models.ModuleVersion
+	// Releases lists the releases of the installed module path in any major version line, leaving out prereleases
+	Releases []string
+}
+
+// MajorVersions looks up the latest version in the major version line of a module and its newest major version
+// through the module proxy. Newer major versions are found under the path with the next major version suffix,
+// e.g. /v3 or .v3 for gopkg.in, and as +incompatible versions of modules without a suffix.
+func (r *Client) MajorVersions(modPath, version string) (MajorVersions, error) {
+	majors, latest, err := majorVersions(modPath, version, r.proxyVersions)
+	if err != nil || latest == "" {
+		return majors, err
+	}
+
+	info, err := r.ModuleInfo(modPath, latest)
+	if err != nil {
+		return majors, errors.Wrapf(err, "unable to get info for %s@%s", modPath, latest)
+	}
+	majors.Latest, err = versionDetailsFromModuleInfo(*info)
+	return majors, err
+}
+
+// MajorVersions looks up the latest version in the major version line of a module and its newest major version
+// among the versions downloaded to the module cache
+func (c ModCache) MajorVersions(modPath, version string) (MajorVersions, error) {
+	majors, latest, err := majorVersions(modPath, version, c.listVersions)
+	if err != nil || latest == "" {
+		return majors, err
+	}
+
+	escapedPath, err := module.EscapePath(modPath)
+	if err != nil {
+		return majors, errors.Wrapf(err, "unable to escape module path %s", modPath)
+	}
+	if details, ok := c.versionDetails(escapedPath, latest); ok {
+		majors.Latest = details
+	} else {
+		majors.Latest.Version = latest
+	}
+	return majors, nil
+}
+
+// proxyVersions lists the versions of a module known to the proxy, with none for modules no proxy serves
+func (r *Client) proxyVersions(modPath string) ([]string, error) {
+	versions, err := r.ModuleVersions(modPath)
+	if errors.Cause(err) == ErrNoProxy {
+		return nil, nil
+	}
+	return versions, err
+}
+
+// listVersions lists the versions of a module in the module cache
+func (c ModCache) listVersions(modPath string) ([]string, error) {
+	escapedPath, err := module.EscapePath(modPath)
+	if err != nil {
+		return nil, nil
+	}
+	return c.versions(escapedPath), nil
+}
+
+// majorVersions finds the newest major version of a module and the latest version in the major version line of the
+// installed one, which it returns separately so that its details are looked up where the versions were listed
+func majorVersions(modPath, version string, list func(modPath string) ([]string, error)) (MajorVersions, string, error) {
+	var majors MajorVersions
+	prefix, pathMajor, ok := module.SplitPathVersion(modPath)
+	if !ok {
+		return majors, "", nil
+	}
+	installedMajor, ok := majorNumber(version)
+	if !ok {
+		return majors, "", nil
+	}
+
+	versions, err := list(modPath)
+	if err != nil {
+		return majors, "", err
+	}
+	latest := latestVersion(versionsInMajor(versions, installedMajor))
+	for _, v := range versions {
+		if semver.IsValid(v) && semver.Prerelease(v) == "" {
+			majors.Releases = append(majors.Releases, v)
+		}
+	}
+
+	// Modules without a major version suffix may have later majors tagged as +incompatible versions
+	newestMajor := installedMajor
+	for _, v := range versions {
+		if n, ok := majorNumber(v); ok && n > newestMajor {
+			newestMajor = n
+			majors.Newest = models.ModuleVersion{Path: modPath}
+		}
+	}
+	if majors.Newest.Path != "" {
+		majors.Newest.Version = latestVersion(versionsInMajor(versions, newestMajor))
+	}
+
+	// Later majors of modules with a go.mod live under the path with the next major version suffix
+	separator := "/"
+	if strings.HasPrefix(pathMajor, ".") {
+		separator = "."
+	}
+	next := newestMajor + 1
+	if next < 2 && separator == "/" {
+		next = 2
+	}
+	for probes := 0; probes < maxMajorProbes; probes++ {
+		majorPath := prefix + separator + "v" + strconv.Itoa(next)
+		versions, err := list(majorPath)
+		if err != nil {
+			return majors, "", err
+		}
+		newest := latestVersion(versionsInMajor(versions, next))
+		if newest == "" {
+			break
+		}
+		majors.Newest = models.ModuleVersion{Path: majorPath, Version: newest}
+		next++
+	}
+
+	return majors, latest, nil
+}
+
+// versionsInMajor keeps the versions with the given major version
+func versionsInMajor(versions []string, major int) []string {
+	var inMajor []string
+	for _, v := range versions {
+		if n, ok := majorNumber(v); ok && n == major {
+			inMajor = append(inMajor, v)
+		}
+	}
+	return inMajor
+}
+
+// majorNumber returns the major version number of a semantic version
+func majorNumber(version string) (int, bool) {
+	major := semver.Major(version)
+	if major == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(major, "v"))
+	return n, err == nil
+}
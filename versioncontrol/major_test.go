@@ -0,0 +1,118 @@
+package versioncontrol
+
+import (
+	"os"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/semver"
+)
+
+func TestClientMajorVersions(t *testing.T) {
+	skipUnrecorded(t, "clientMajorVersions")
+	r, c, err := SetupHTTPRecord("clientMajorVersions")
+	if err != nil {
+		t.Fatalf("unable to setup http recorder, %v", err)
+	}
+	defer r.Stop()
+
+	request := Client{
+		HttpClient: c,
+		GoProxy:    GoProxy{Proxy: "https://proxy.golang.org"},
+	}
+
+	// The recorded modules are live, so only the releases that closed a major version line are compared exactly
+	tests := []struct {
+		description     string
+		path            string
+		version         string
+		wantLatest      models.VersionDetails
+		wantNewestPath  string
+		wantNewestSince string
+	}{
+		{
+			description:     "should report the latest release of the major version and the newest major module",
+			path:            "github.com/pelletier/go-toml",
+			version:         "v1.9.4",
+			wantLatest:      models.VersionDetails{Version: "v1.9.5", Time: "2022-01-05T14:17:32Z"},
+			wantNewestPath:  "github.com/pelletier/go-toml/v2",
+			wantNewestSince: "v2.4.3",
+		},
+		{
+			description:     "should look past the major version suffix of the installed module",
+			path:            "github.com/jackc/pgx/v4",
+			version:         "v4.18.1",
+			wantLatest:      models.VersionDetails{Version: "v4.18.3", Time: "2024-03-09T18:15:53Z"},
+			wantNewestPath:  "github.com/jackc/pgx/v5",
+			wantNewestSince: "v5.11.0",
+		},
+		{
+			description:     "should report +incompatible versions as newer majors",
+			path:            "github.com/dgrijalva/jwt-go",
+			version:         "v1.0.2",
+			wantLatest:      models.VersionDetails{Version: "v1.0.2", Time: "2014-08-26T20:51:41Z"},
+			wantNewestPath:  "github.com/dgrijalva/jwt-go/v4",
+			wantNewestSince: "v4.0.0-preview1",
+		},
+		{
+			description:     "should handle gopkg.in major versions",
+			path:            "gopkg.in/yaml.v2",
+			version:         "v2.2.8",
+			wantLatest:      models.VersionDetails{Version: "v2.4.0", Time: "2020-11-17T15:46:20Z"},
+			wantNewestPath:  "gopkg.in/yaml.v3",
+			wantNewestSince: "v3.0.1",
+		},
+		{
+			description: "should report no latest version for untagged modules",
+			path:        "github.com/golang/groupcache",
+			version:     "v0.0.0-20210331224755-41bb18bfe9da",
+		},
+		{
+			description: "should report nothing for modules no proxy serves",
+			path:        "example.com/private",
+			version:     "v1.0.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := request.MajorVersions(test.path, test.version)
+			if err != nil {
+				t.Fatalf("error returned from MajorVersions, err: %v", err)
+			}
+			assert.Equal(t, test.wantLatest, got.Latest)
+			assert.Equal(t, test.wantNewestPath, got.Newest.Path)
+			if test.wantNewestSince == "" {
+				assert.Empty(t, got.Newest.Version)
+			} else {
+				assert.True(t, semver.Compare(got.Newest.Version, test.wantNewestSince) >= 0,
+					"newest %q should not be older than %s", got.Newest.Version, test.wantNewestSince)
+			}
+			if test.wantLatest.Version == "" {
+				assert.Empty(t, got.Releases)
+			} else {
+				assert.Contains(t, got.Releases, test.version)
+				assert.Contains(t, got.Releases, test.wantLatest.Version)
+			}
+		})
+	}
+}
+
+func TestModCacheMajorVersions(t *testing.T) {
+	dir := writeModCache(t, map[string]string{
+		"cache/download/github.com/!burnt!sushi/toml/@v/list":        "v0.3.0\nv0.3.1\n",
+		"cache/download/github.com/!burnt!sushi/toml/@v/v0.3.1.info": `{"Version":"v0.3.1","Time":"2018-08-15T10:47:33Z"}`,
+		"cache/download/github.com/!burnt!sushi/toml/v2/@v/list":     "v2.0.0\n",
+	})
+	defer os.RemoveAll(dir)
+
+	got, err := ModCache{Dir: dir}.MajorVersions("github.com/BurntSushi/toml", "v0.3.0")
+	if assert.NoError(t, err) {
+		assert.Equal(t, MajorVersions{
+			Latest:   models.VersionDetails{Version: "v0.3.1", Time: "2018-08-15T10:47:33Z"},
+			Newest:   models.ModuleVersion{Path: "github.com/BurntSushi/toml/v2", Version: "v2.0.0"},
+			Releases: []string{"v0.3.0", "v0.3.1"},
+		}, got)
+	}
+}
@@ -0,0 +1,159 @@
+package versioncontrol
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"golang.org/x/mod/module"
+)
+
+// Fields reported as undetermined when the module cache does not hold the data needed for them
+const (
+	UndeterminedInstalledTime = "installed.time"
+	UndeterminedLatest        = "latest"
+	UndeterminedLatestTime    = "latest.time"
+	UndeterminedLicense       = "license"
+)
+
+// ModCache locates the module cache filled by the go command, https://go.dev/ref/mod#module-cache
+type ModCache struct {
+	// Dir is the root of the module cache, the value of GOMODCACHE
+	Dir string
+}
+
+// ModCacheFromEnv locates the module cache the same way as the go command: GOMODCACHE,
+// else pkg/mod in the first GOPATH entry, else in $HOME/go
+func ModCacheFromEnv() ModCache {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return ModCache{Dir: dir}
+	}
+
+	gopath := filepath.SplitList(os.Getenv("GOPATH"))
+	if len(gopath) > 0 && gopath[0] != "" {
+		return ModCache{Dir: filepath.Join(gopath[0], "pkg", "mod")}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ModCache{}
+	}
+	return ModCache{Dir: filepath.Join(home, "go", "pkg", "mod")}
+}
+
+// ReportObjFromModCache creates a report object without using the network, from the .info and list files the go
+// command keeps in the download cache and from the extracted module tree. The latest version is the latest one
+// downloaded to this machine. Fields that the cache cannot provide are listed in Undetermined instead of failing.
+func ReportObjFromModCache(dep models.Dependency, r Client) (*models.ReportObject, error) {
+	modPath := dep.Path
+	if modPath == "" {
+		modPath = dep.Name
+	}
+	reportObject := models.ReportObject{
+		Name:    dep.Name,
+		Source:  dep.Source,
+		Website: "https://pkg.go.dev/" + modPath,
+		Installed: models.VersionDetails{
+			Version: dep.Version,
+		},
+	}
+	// Paths that are not valid module paths, like packages from Gopkg.lock, cannot be in the module cache
+	escapedPath, err := module.EscapePath(modPath)
+	if err != nil {
+		reportObject.Undetermined = []string{UndeterminedInstalledTime, UndeterminedLatest, UndeterminedLicense}
+		return &reportObject, nil
+	}
+
+	var undetermined []string
+	license := ""
+	if dep.Version != "" {
+		version := IncompatibleVersion(modPath, dep.Version)
+		if installed, ok := r.ModCache.versionDetails(escapedPath, version); ok {
+			reportObject.Installed = installed
+			reportObject.Installed.Version = dep.Version
+		} else if dep.CommitTime != "" {
+			// Pseudo-versions carry their commit time, so modules that were never downloaded still have one
+			reportObject.Installed.Time = dep.CommitTime
+		} else {
+			undetermined = append(undetermined, UndeterminedInstalledTime)
+		}
+		license = r.ModCache.license(escapedPath, version)
+	} else {
+		undetermined = append(undetermined, UndeterminedInstalledTime)
+	}
+
+	latest := latestVersion(r.ModCache.versions(escapedPath))
+	if latest == "" {
+		undetermined = append(undetermined, UndeterminedLatest)
+	} else if details, ok := r.ModCache.versionDetails(escapedPath, latest); ok {
+		reportObject.Latest = details
+	} else {
+		reportObject.Latest.Version = latest
+		undetermined = append(undetermined, UndeterminedLatestTime)
+	}
+
+	if license == "" {
+		undetermined = append(undetermined, UndeterminedLicense)
+	}
+	reportObject.License = license
+	reportObject.Undetermined = undetermined
+
+	return &reportObject, nil
+}
+
+func (c ModCache) downloadDir(escapedPath string) string {
+	return filepath.Join(c.Dir, "cache", "download", filepath.FromSlash(escapedPath), "@v")
+}
+
+// versions returns the versions listed in the list file of a module
+func (c ModCache) versions(escapedPath string) []string {
+	data, err := ioutil.ReadFile(filepath.Join(c.downloadDir(escapedPath), "list"))
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(data))
+}
+
+// versionDetails reads the .info file of a module version
+func (c ModCache) versionDetails(escapedPath, version string) (models.VersionDetails, bool) {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return models.VersionDetails{}, false
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(c.downloadDir(escapedPath), escapedVersion+".info"))
+	if err != nil {
+		return models.VersionDetails{}, false
+	}
+
+	var info models.ModuleInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return models.VersionDetails{}, false
+	}
+	details, err := versionDetailsFromModuleInfo(info)
+	if err != nil || details.Time == "" {
+		return models.VersionDetails{}, false
+	}
+	return details, true
+}
+
+// license detects the license of a module version from its extracted tree, or returns "" when it was not extracted
+func (c ModCache) license(escapedPath, version string) string {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return ""
+	}
+
+	dir := filepath.Join(c.Dir, filepath.FromSlash(escapedPath)+"@"+escapedVersion)
+	for _, fileName := range licenseFileNames {
+		data, err := ioutil.ReadFile(filepath.Join(dir, fileName))
+		if err != nil {
+			continue
+		}
+		return detectLicense(string(data))
+	}
+	return ""
+}
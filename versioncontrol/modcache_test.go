@@ -0,0 +1,181 @@
+package versioncontrol
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeModCache creates a module cache with the given files, relative to its root
+func writeModCache(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "dep-report-modcache")
+	if err != nil {
+		t.Fatalf("unable to create module cache: %v", err)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("unable to create module cache: %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("unable to create module cache: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestReportObjFromModCache(t *testing.T) {
+	dir := writeModCache(t, map[string]string{
+		"cache/download/github.com/!burnt!sushi/toml/@v/list":        "v0.3.0\nv0.3.1\nv0.4.0-rc.1\n",
+		"cache/download/github.com/!burnt!sushi/toml/@v/v0.3.1.info": `{"Version":"v0.3.1","Time":"2018-08-15T10:47:33Z"}`,
+		"cache/download/github.com/!burnt!sushi/toml/@v/v0.3.0.info": `{"Version":"v0.3.0","Time":"2017-03-28T06:15:53Z"}`,
+		"github.com/!burnt!sushi/toml@v0.3.1/COPYING":                "Permission is hereby granted, free of charge, to any person obtaining a copy",
+		"cache/download/github.com/pkg/errors/@v/list":               "v0.9.1\n",
+		"cache/download/github.com/pkg/errors/@v/v0.9.1.info":        `{"Version":"v0.9.1","Time":"2020-01-14T19:47:44Z","Origin":{"VCS":"git","URL":"https://github.com/pkg/errors","Hash":"614d223910a179a466c1767a985424175c39b465"}}`,
+	})
+	defer os.RemoveAll(dir)
+
+	request := Client{
+		ModCache: ModCache{Dir: dir},
+	}
+
+	tests := []struct {
+		description      string
+		dependency       models.Dependency
+		wantReportObject *models.ReportObject
+	}{
+		{
+			description: "should report everything the module cache holds",
+			dependency: models.Dependency{
+				Name:    "github.com/BurntSushi/toml",
+				Path:    "github.com/BurntSushi/toml",
+				Version: "v0.3.1",
+				Source:  "modcache",
+			},
+			wantReportObject: &models.ReportObject{
+				Name:    "github.com/BurntSushi/toml",
+				Source:  "modcache",
+				License: "MIT",
+				Website: "https://pkg.go.dev/github.com/BurntSushi/toml",
+				Installed: models.VersionDetails{
+					Version: "v0.3.1",
+					Time:    "2018-08-15T10:47:33Z",
+				},
+				Latest: models.VersionDetails{
+					Version: "v0.3.1",
+					Time:    "2018-08-15T10:47:33Z",
+				},
+			},
+		},
+		{
+			description: "should mark the fields the module cache cannot provide",
+			dependency: models.Dependency{
+				Name:    "github.com/pkg/errors",
+				Path:    "github.com/pkg/errors",
+				Version: "v0.8.1",
+				Source:  "modcache",
+			},
+			wantReportObject: &models.ReportObject{
+				Name:    "github.com/pkg/errors",
+				Source:  "modcache",
+				Website: "https://pkg.go.dev/github.com/pkg/errors",
+				Installed: models.VersionDetails{
+					Version: "v0.8.1",
+				},
+				Latest: models.VersionDetails{
+					Version: "v0.9.1",
+					Time:    "2020-01-14T19:47:44Z",
+					Commit:  "614d223910a179a466c1767a985424175c39b465",
+				},
+				Undetermined: []string{"installed.time", "license"},
+			},
+		},
+		{
+			description: "should take the installed time from pseudo-versions missing from the cache",
+			dependency: models.Dependency{
+				Name:       "github.com/pkg/errors",
+				Path:       "github.com/pkg/errors",
+				Version:    "v0.9.2-0.20201214064552-5dd12d0cfe7f",
+				Revision:   "5dd12d0cfe7f",
+				CommitTime: "2020-12-14T06:45:52Z",
+				Source:     "modcache",
+			},
+			wantReportObject: &models.ReportObject{
+				Name:    "github.com/pkg/errors",
+				Source:  "modcache",
+				Website: "https://pkg.go.dev/github.com/pkg/errors",
+				Installed: models.VersionDetails{
+					Version: "v0.9.2-0.20201214064552-5dd12d0cfe7f",
+					Time:    "2020-12-14T06:45:52Z",
+				},
+				Latest: models.VersionDetails{
+					Version: "v0.9.1",
+					Time:    "2020-01-14T19:47:44Z",
+					Commit:  "614d223910a179a466c1767a985424175c39b465",
+				},
+				Undetermined: []string{"license"},
+			},
+		},
+		{
+			description: "should report modules missing from the cache without failing",
+			dependency: models.Dependency{
+				Name:     "gopkg.in/fake",
+				Revision: "12345",
+				Source:   "modcache",
+			},
+			wantReportObject: &models.ReportObject{
+				Name:         "gopkg.in/fake",
+				Source:       "modcache",
+				Website:      "https://pkg.go.dev/gopkg.in/fake",
+				Undetermined: []string{"installed.time", "latest", "license"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			reportObject, err := ReportObjFromModCache(test.dependency, request)
+			if err != nil {
+				t.Errorf("error returned from ReportObjFromModCache, err: %v", err)
+			}
+			assert.EqualValues(t, test.wantReportObject, reportObject)
+		})
+	}
+}
+
+func TestModCacheFromEnv(t *testing.T) {
+	tests := []struct {
+		description string
+		gomodcache  string
+		gopath      string
+		wantDir     string
+	}{
+		{
+			description: "should prefer GOMODCACHE",
+			gomodcache:  "/cache/mod",
+			gopath:      "/go",
+			wantDir:     "/cache/mod",
+		},
+		{
+			description: "should use the first GOPATH entry",
+			gopath:      "/first" + string(os.PathListSeparator) + "/second",
+			wantDir:     filepath.Join("/first", "pkg", "mod"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer os.Setenv("GOMODCACHE", os.Getenv("GOMODCACHE"))
+			defer os.Setenv("GOPATH", os.Getenv("GOPATH"))
+			os.Setenv("GOMODCACHE", test.gomodcache)
+			os.Setenv("GOPATH", test.gopath)
+
+			assert.Equal(t, test.wantDir, ModCacheFromEnv().Dir)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package versioncontrol
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/pkg/errors"
+)
+
+// Sources reported for dependencies, one per provider
+const (
+	GITHUB    = "github"
+	GITLAB    = "gitlab"
+	GERRIT    = "gerrit"
+	BITBUCKET = "bitbucket"
+	GITEA     = "gitea"
+)
+
+// Provider resolves the report data of dependencies hosted on one kind of version control host
+type Provider interface {
+	// Name is the source reported for dependencies resolved by this provider, e.g. github
+	Name() string
+	// Match reports whether the provider serves repositories on the given host
+	Match(host string, r Client) bool
+	// Website returns the URL reported as the website of the dependency
+	Website(dep models.Dependency, r Client) (string, error)
+	// Installed resolves the commit and commit time of the installed revision
+	Installed(dep models.Dependency, r Client) (models.VersionDetails, error)
+	// Latest resolves the latest commit on the default branch and the latest released version
+	Latest(dep models.Dependency, r Client) (models.VersionDetails, error)
+	// License resolves the SPDX identifier of the dependency license
+	License(dep models.Dependency, r Client) (string, error)
+}
+
+// hostBound is implemented by providers that can only resolve the hosts they match, such as the public GitHub API or the
+// Gerrit projects with GitHub mirrors, so other hosts cannot be routed to them
+type hostBound interface {
+	boundToMatchedHosts()
+}
+
+// ReportObjFromProvider uses the data in a dependency object and creates a report object with the given provider
+func ReportObjFromProvider(p Provider, dep models.Dependency, r Client) (*models.ReportObject, error) {
+	website, err := p.Website(dep, r)
+	if err != nil {
+		return nil, err
+	}
+
+	reportObject := models.ReportObject{
+		Name:    dep.Name,
+		Website: website,
+		Source:  dep.Source,
+	}
+
+	reportObject.License, err = p.License(dep, r)
+	if err != nil {
+		return nil, err
+	}
+
+	reportObject.Installed, err = p.Installed(dep, r)
+	if err != nil {
+		return nil, err
+	}
+
+	reportObject.Latest, err = p.Latest(dep, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if lister, ok := p.(TagLister); ok {
+		tags, err := lister.Tags(dep, r)
+		if err != nil {
+			return nil, err
+		}
+		reportObject.Latest.Version, reportObject.LatestPrerelease = latestTagged(tags, tagPrefix(dep), r.ExcludePrereleases)
+	}
+
+	return &reportObject, nil
+}
+
+// Registry holds an ordered list of providers. The first provider matching the host of a dependency resolves it,
+// unless the host has been routed to a provider explicitly.
+type Registry struct {
+	providers []Provider
+	hosts     map[string]Provider
+}
+
+// NewRegistry creates a registry consulting the given providers in order
+func NewRegistry(providers ...Provider) *Registry {
+	return &Registry{
+		providers: providers,
+		hosts:     map[string]Provider{},
+	}
+}
+
+// DefaultRegistry creates a registry with every provider in this package
+func DefaultRegistry() *Registry {
+	return NewRegistry(
+		GerritProvider{},
+		GithubProvider{},
+		GitlabProvider{},
+		BitbucketProvider{},
+		GiteaProvider{},
+	)
+}
+
+// Register adds a provider after the ones already registered
+func (reg *Registry) Register(p Provider) {
+	reg.providers = append(reg.providers, p)
+}
+
+// RegisterHost routes every dependency hosted on host to the registered provider with the given name.
+// Providers bound to their own API hosts, like github and gerrit, only accept the hosts they already match.
+func (reg *Registry) RegisterHost(host, name string) error {
+	host = strings.ToLower(host)
+	for _, p := range reg.providers {
+		if p.Name() != name {
+			continue
+		}
+		if _, ok := p.(hostBound); ok && !p.Match(host, Client{}) {
+			return fmt.Errorf("provider %s only queries its own hosts and cannot serve %s", name, host)
+		}
+		reg.hosts[host] = p
+		return nil
+	}
+	return fmt.Errorf("no provider named %s is registered", name)
+}
+
+// RegisterHostsFromEnv routes hosts listed in DEP_REPORT_HOSTS, a comma separated list of host=provider pairs
+// such as git.example.com=gitlab,code.example.com=gitea
+func (reg *Registry) RegisterHostsFromEnv() error {
+	env := os.Getenv("DEP_REPORT_HOSTS")
+	if env == "" {
+		return nil
+	}
+
+	for _, pair := range strings.Split(env, ",") {
+		hostAndName := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(hostAndName) != 2 {
+			return fmt.Errorf("invalid DEP_REPORT_HOSTS entry %q, expected host=provider", pair)
+		}
+		if err := reg.RegisterHost(strings.TrimSpace(hostAndName[0]), strings.TrimSpace(hostAndName[1])); err != nil {
+			return errors.Wrap(err, "invalid DEP_REPORT_HOSTS")
+		}
+	}
+	return nil
+}
+
+// Lookup returns the provider for a dependency based on the host of its repo, or of its name when the repo is unknown
+func (reg *Registry) Lookup(dep models.Dependency, r Client) (Provider, bool) {
+	host := repoHost(dep)
+	if host == "" {
+		return nil, false
+	}
+
+	if p, ok := reg.hosts[host]; ok {
+		return p, true
+	}
+	for _, p := range reg.providers {
+		if p.Match(host, r) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// repoHost returns the lower cased host of the dependency repo
+func repoHost(dep models.Dependency) string {
+	repo := dep.Repo
+	if repo == "" {
+		repo = "https://" + dep.RootPath()
+	}
+
+	u, err := url.Parse(repo)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// hostMatches reports whether host is one of hosts or a subdomain of one of them
+func hostMatches(host string, hosts ...string) bool {
+	for _, h := range hosts {
+		h = strings.ToLower(h)
+		if h != "" && (host == h || strings.HasSuffix(host, "."+h)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostOf returns the host of a configured base URL
+func hostOf(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
@@ -0,0 +1,201 @@
+package versioncontrol
+
+import (
+	"os"
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type testProvider struct {
+	name string
+	host string
+}
+
+func (p testProvider) Name() string                     { return p.name }
+func (p testProvider) Match(host string, r Client) bool { return host == p.host }
+func (p testProvider) Website(dep models.Dependency, r Client) (string, error) {
+	return "https://" + p.host, nil
+}
+func (p testProvider) Installed(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	return models.VersionDetails{Commit: dep.Revision}, nil
+}
+func (p testProvider) Latest(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	return models.VersionDetails{}, nil
+}
+func (p testProvider) License(dep models.Dependency, r Client) (string, error) {
+	return "MIT", nil
+}
+
+func TestRegistryLookup(t *testing.T) {
+	registry := DefaultRegistry()
+	registry.Register(testProvider{name: "sourcehut", host: "git.sr.ht"})
+	if err := registry.RegisterHost("git.corp.example.com", GITEA); err != nil {
+		t.Fatalf("unable to register host: %v", err)
+	}
+
+	request := Client{
+		Gitlab:    Gitlab{URL: "https://gitlab.example.com"},
+		Bitbucket: Bitbucket{ServerURL: "https://bitbucket.example.com"},
+		Gitea:     Gitea{Hosts: []string{"codeberg.org"}},
+	}
+
+	tests := []struct {
+		description  string
+		dependency   models.Dependency
+		wantProvider string
+	}{
+		{
+			description:  "should match github by host",
+			dependency:   models.Dependency{Name: "github.com/pkg/errors"},
+			wantProvider: GITHUB,
+		},
+		{
+			description:  "should match the github api host",
+			dependency:   models.Dependency{Name: "cloud.google.com/go", Repo: "https://api.github.com/repos/googleapis/google-cloud-go"},
+			wantProvider: GITHUB,
+		},
+		{
+			description:  "should prefer the repo over the package name",
+			dependency:   models.Dependency{Name: "golang.org/x/text", Repo: "https://go.googlesource.com/text"},
+			wantProvider: GERRIT,
+		},
+		{
+			description:  "should match the mapped gerrit projects",
+			dependency:   models.Dependency{Name: "cloud.google.com/go", Repo: "https://code-review.googlesource.com/projects/gocloud"},
+			wantProvider: GERRIT,
+		},
+		{
+			description: "should not match gerrit hosts without a github mirror",
+			dependency:  models.Dependency{Name: "go.chromium.org/luci", Repo: "https://chromium.googlesource.com/infra/luci/luci-go"},
+		},
+		{
+			description: "should not match hosts that only contain a provider name",
+			dependency:  models.Dependency{Name: "github.example.org/repo/project"},
+		},
+		{
+			description:  "should match a configured gitlab instance",
+			dependency:   models.Dependency{Name: "gitlab.example.com/group/project"},
+			wantProvider: GITLAB,
+		},
+		{
+			description:  "should match a configured bitbucket server",
+			dependency:   models.Dependency{Name: "bitbucket.example.com/scm/dep/example.git"},
+			wantProvider: BITBUCKET,
+		},
+		{
+			description:  "should match gitea hosts case insensitively",
+			dependency:   models.Dependency{Name: "Codeberg.org/dep-report/example"},
+			wantProvider: GITEA,
+		},
+		{
+			description:  "should route hosts registered explicitly",
+			dependency:   models.Dependency{Name: "git.corp.example.com/team/lib"},
+			wantProvider: GITEA,
+		},
+		{
+			description:  "should match providers registered by library users",
+			dependency:   models.Dependency{Name: "git.sr.ht/~sircmpwn/getopt"},
+			wantProvider: "sourcehut",
+		},
+		{
+			description: "should not match unknown hosts",
+			dependency:  models.Dependency{Name: "gopkg.in/fake"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			provider, ok := registry.Lookup(test.dependency, request)
+			if test.wantProvider == "" {
+				assert.False(t, ok)
+				return
+			}
+			if assert.True(t, ok) {
+				assert.Equal(t, test.wantProvider, provider.Name())
+			}
+		})
+	}
+}
+
+func TestRegisterHostsFromEnv(t *testing.T) {
+	tests := []struct {
+		description string
+		env         string
+		host        string
+		wantSource  string
+		wantError   bool
+	}{
+		{
+			description: "should route listed hosts",
+			env:         "git.example.com=gitlab, code.example.com=gitea",
+			host:        "code.example.com",
+			wantSource:  GITEA,
+		},
+		{
+			description: "should reject entries without a provider",
+			env:         "git.example.com",
+			wantError:   true,
+		},
+		{
+			description: "should reject unknown providers",
+			env:         "git.example.com=svn",
+			wantError:   true,
+		},
+		{
+			description: "should reject other hosts for github, which only queries api.github.com",
+			env:         "github.example.com=github",
+			wantError:   true,
+		},
+		{
+			description: "should reject other hosts for gerrit, which only resolves go.googlesource.com and mapped projects",
+			env:         "gerrit.example.com=gerrit",
+			wantError:   true,
+		},
+		{
+			description: "should accept hosts that the provider already queries",
+			env:         "GitHub.com=github",
+			host:        "github.com",
+			wantSource:  GITHUB,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			os.Setenv("DEP_REPORT_HOSTS", test.env)
+			defer os.Unsetenv("DEP_REPORT_HOSTS")
+
+			registry := DefaultRegistry()
+			err := registry.RegisterHostsFromEnv()
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			provider, ok := registry.Lookup(models.Dependency{Name: test.host + "/team/lib"}, Client{})
+			if assert.True(t, ok) {
+				assert.Equal(t, test.wantSource, provider.Name())
+			}
+		})
+	}
+}
+
+func TestReportObjFromProvider(t *testing.T) {
+	dep := models.Dependency{
+		Name:     "git.sr.ht/~sircmpwn/getopt",
+		Revision: "daed05f",
+		Source:   "sourcehut",
+	}
+
+	reportObject, err := ReportObjFromProvider(testProvider{name: "sourcehut", host: "git.sr.ht"}, dep, Client{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, &models.ReportObject{
+		Name:      "git.sr.ht/~sircmpwn/getopt",
+		Source:    "sourcehut",
+		License:   "MIT",
+		Website:   "https://git.sr.ht",
+		Installed: models.VersionDetails{Commit: "daed05f"},
+	}, reportObject)
+}
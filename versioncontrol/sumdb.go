@@ -0,0 +1,186 @@
+package versioncontrol
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/sumdb"
+)
+
+// Results of verifying a module against the checksum database
+const (
+	SumVerified = "verified"
+	SumMismatch = "mismatch"
+	// SumSkipped is reported for modules that are excluded by GONOSUMDB or have no hashes to verify
+	SumSkipped = "skipped"
+)
+
+// defaultSumDB is the verifier key of sum.golang.org, which the go command uses when GOSUMDB is unset
+const defaultSumDB = "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ky0Ic9Ag4Wx3shG"
+
+// ErrNoSumDB is returned when verification is requested but GOSUMDB is off
+var ErrNoSumDB = errors.New("checksum database is off")
+
+// SumDB configures the checksum database go.sum hashes are verified against
+// https://go.dev/ref/mod#checksum-database
+type SumDB struct {
+	// Key is the verifier key of the database, e.g. sum.golang.org+033de0ae+Ac4z..., empty when it is off
+	Key string
+	// URL is where the database is served, https:// followed by the database name by default
+	URL string
+	// NoSumDB is a comma separated list of module path prefix patterns that are not verified
+	NoSumDB string
+}
+
+// SumDBFromEnv builds a SumDB from the GOSUMDB, GONOSUMDB and GOPRIVATE environment variables,
+// following the same defaults as the go command
+func SumDBFromEnv() SumDB {
+	var db SumDB
+	switch gosumdb := strings.Fields(os.Getenv("GOSUMDB")); {
+	case len(gosumdb) == 0, gosumdb[0] == "sum.golang.org":
+		db.Key = defaultSumDB
+	case gosumdb[0] == "off":
+	default:
+		db.Key = gosumdb[0]
+		if len(gosumdb) > 1 {
+			db.URL = gosumdb[1]
+		}
+	}
+
+	db.NoSumDB = os.Getenv("GONOSUMDB")
+	if db.NoSumDB == "" {
+		db.NoSumDB = os.Getenv("GOPRIVATE")
+	}
+	return db
+}
+
+// SumVerifier verifies go.sum hashes against a checksum database, checking the database proofs like the go command.
+// It is safe for concurrent use.
+type SumVerifier struct {
+	client *sumdb.Client
+	host   string
+}
+
+// NewSumVerifier creates a SumVerifier sending its requests with the given client
+func NewSumVerifier(db SumDB, r Client) (*SumVerifier, error) {
+	if db.Key == "" {
+		return nil, ErrNoSumDB
+	}
+
+	url := db.URL
+	if url == "" {
+		url = "https://" + strings.SplitN(db.Key, "+", 2)[0]
+	}
+
+	client := sumdb.NewClient(&sumDBOps{
+		request: r,
+		url:     strings.TrimSuffix(url, "/"),
+		key:     db.Key,
+		config:  map[string][]byte{},
+		cache:   map[string][]byte{},
+	})
+	if db.NoSumDB != "" {
+		client.SetGONOSUMDB(db.NoSumDB)
+	}
+	return &SumVerifier{client: client, host: hostOf(url)}, nil
+}
+
+// Host returns the host serving the checksum database
+func (v *SumVerifier) Host() string {
+	return v.host
+}
+
+// Verify compares the hashes of a module version with those in the checksum database. Empty hashes are not compared.
+func (v *SumVerifier) Verify(path, version, sum, goModSum string) (string, error) {
+	if sum == "" && goModSum == "" {
+		return SumSkipped, nil
+	}
+
+	result := SumVerified
+	for _, check := range []struct{ version, hash string }{{version, sum}, {version + "/go.mod", goModSum}} {
+		if check.hash == "" {
+			continue
+		}
+
+		lines, err := v.client.Lookup(path, check.version)
+		if err == sumdb.ErrGONOSUMDB {
+			return SumSkipped, nil
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to look up %s@%s in the checksum database", path, check.version)
+		}
+
+		want := path + " " + check.version + " " + check.hash
+		found := false
+		for _, line := range lines {
+			found = found || line == want
+		}
+		if !found {
+			result = SumMismatch
+		}
+	}
+	return result, nil
+}
+
+// sumDBOps serves the requests of the checksum database client through a Client,
+// keeping the latest signed tree and the verified records in memory for the run
+type sumDBOps struct {
+	request Client
+	url     string
+	key     string
+
+	mu     sync.Mutex
+	config map[string][]byte
+	cache  map[string][]byte
+}
+
+func (o *sumDBOps) ReadRemote(path string) ([]byte, error) {
+	return o.request.get(o.url+path, nil)
+}
+
+func (o *sumDBOps) ReadConfig(file string) ([]byte, error) {
+	if file == "key" {
+		return []byte(o.key), nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.config[file], nil
+}
+
+func (o *sumDBOps) WriteConfig(file string, old, new []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !bytes.Equal(o.config[file], old) {
+		return sumdb.ErrWriteConflict
+	}
+	o.config[file] = new
+	return nil
+}
+
+func (o *sumDBOps) ReadCache(file string) ([]byte, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	data, ok := o.cache[file]
+	if !ok {
+		return nil, errors.Errorf("%s is not cached", file)
+	}
+	return data, nil
+}
+
+func (o *sumDBOps) WriteCache(file string, data []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.cache[file] = data
+}
+
+func (o *sumDBOps) Log(msg string) {}
+
+// SecurityError is called when the database serves inconsistent proofs. The lookup then fails with sumdb.ErrSecurity,
+// which the report records for the module.
+func (o *sumDBOps) SecurityError(msg string) {}
@@ -0,0 +1,157 @@
+package versioncontrol
+
+import (
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// newTestSumDB starts a checksum database serving the given go.sum lines by module version,
+// returning the server and its verifier key
+func newTestSumDB(t *testing.T, gosum map[string]string) (*httptest.Server, string) {
+	signer, verifier, err := note.GenerateKey(rand.Reader, "sum.example.test")
+	if err != nil {
+		t.Fatalf("unable to generate checksum database key: %v", err)
+	}
+
+	ops := sumdb.NewTestServer(signer, func(path, vers string) ([]byte, error) {
+		lines, ok := gosum[path+"@"+vers]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(lines), nil
+	})
+	return httptest.NewServer(sumdb.NewServer(ops)), verifier
+}
+
+func TestSumVerifier(t *testing.T) {
+	server, key := newTestSumDB(t, map[string]string{
+		"github.com/pkg/errors@v0.9.1": "github.com/pkg/errors v0.9.1 h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=\n" +
+			"github.com/pkg/errors v0.9.1/go.mod h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=\n",
+	})
+	defer server.Close()
+
+	verifier, err := NewSumVerifier(SumDB{Key: key, URL: server.URL, NoSumDB: "github.com/private"}, Client{
+		HttpClient: http.DefaultClient,
+		Retry:      Retry{BaseDelay: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("unable to create verifier: %v", err)
+	}
+
+	tests := []struct {
+		description string
+		path        string
+		version     string
+		sum         string
+		goModSum    string
+		wantResult  string
+		wantError   bool
+	}{
+		{
+			description: "should verify matching hashes",
+			path:        "github.com/pkg/errors",
+			version:     "v0.9.1",
+			sum:         "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=",
+			goModSum:    "h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=",
+			wantResult:  "verified",
+		},
+		{
+			description: "should verify go.mod hashes alone",
+			path:        "github.com/pkg/errors",
+			version:     "v0.9.1",
+			goModSum:    "h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=",
+			wantResult:  "verified",
+		},
+		{
+			description: "should flag mismatching hashes",
+			path:        "github.com/pkg/errors",
+			version:     "v0.9.1",
+			sum:         "h1:tampered0000000000000000000000000000000000=",
+			goModSum:    "h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=",
+			wantResult:  "mismatch",
+		},
+		{
+			description: "should skip modules excluded from the checksum database",
+			path:        "github.com/private/repo",
+			version:     "v1.0.0",
+			sum:         "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=",
+			wantResult:  "skipped",
+		},
+		{
+			description: "should skip modules without hashes",
+			path:        "github.com/pkg/errors",
+			version:     "v0.9.1",
+			wantResult:  "skipped",
+		},
+		{
+			description: "should fail for modules the checksum database does not know",
+			path:        "github.com/pkg/errors",
+			version:     "v0.8.1",
+			sum:         "h1:iURUrRGxPUNPdy5/HRSm+Yj6okJ6UtLINN0Q9M4+h3I=",
+			wantError:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := verifier.Verify(test.path, test.version, test.sum, test.goModSum)
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.wantResult, result)
+			}
+		})
+	}
+}
+
+func TestSumDBFromEnv(t *testing.T) {
+	tests := []struct {
+		description string
+		gosumdb     string
+		gonosumdb   string
+		goprivate   string
+		wantSumDB   SumDB
+	}{
+		{
+			description: "should default to sum.golang.org",
+			wantSumDB:   SumDB{Key: defaultSumDB},
+		},
+		{
+			description: "should turn off verification",
+			gosumdb:     "off",
+		},
+		{
+			description: "should read a key and url",
+			gosumdb:     "sum.example.com+abcd1234+AAAA https://sum.example.com/mirror",
+			goprivate:   "github.com/private",
+			wantSumDB:   SumDB{Key: "sum.example.com+abcd1234+AAAA", URL: "https://sum.example.com/mirror", NoSumDB: "github.com/private"},
+		},
+		{
+			description: "should prefer GONOSUMDB to GOPRIVATE",
+			gonosumdb:   "example.com",
+			goprivate:   "github.com/private",
+			wantSumDB:   SumDB{Key: defaultSumDB, NoSumDB: "example.com"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			for key, value := range map[string]string{"GOSUMDB": test.gosumdb, "GONOSUMDB": test.gonosumdb, "GOPRIVATE": test.goprivate} {
+				defer os.Setenv(key, os.Getenv(key))
+				os.Setenv(key, value)
+			}
+
+			assert.Equal(t, test.wantSumDB, SumDBFromEnv())
+		})
+	}
+}
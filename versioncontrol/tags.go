@@ -0,0 +1,75 @@
+package versioncontrol
+
+import (
+	"strings"
+
+	"github.com/1Password/dep-report/models"
+	"golang.org/x/mod/semver"
+)
+
+// Tags are listed in pages of tagsPerPage, up to maxTagPages pages
+const (
+	tagsPerPage = 100
+	maxTagPages = 50
+)
+
+// TagLister is implemented by providers that can list the tags of a repository. The latest version of the
+// dependencies they resolve is the highest semantic version tag rather than the version reported by Latest.
+type TagLister interface {
+	// Tags lists the names of all tags of the repository hosting the dependency
+	Tags(dep models.Dependency, r Client) ([]string, error)
+}
+
+// latestTagged picks the latest release and the latest prerelease among the tags of a repository. Only tags that are
+// canonical semantic versions after the prefix count, e.g. sub/module/v1.2.3 for a module in the sub/module directory.
+// Without any release, the latest prerelease is the latest version unless prereleases are excluded.
+// The latest prerelease is only returned when it is newer than the latest version.
+func latestTagged(tags []string, prefix string, excludePrereleases bool) (string, string) {
+	latest := ""
+	prerelease := ""
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, prefix) {
+			continue
+		}
+		version := strings.TrimPrefix(tag, prefix)
+		// Shorthands like v2 and versions with build metadata are not module versions
+		if semver.Canonical(version) != version {
+			continue
+		}
+		if semver.Prerelease(version) == "" {
+			if semver.Compare(version, latest) > 0 {
+				latest = version
+			}
+		} else if semver.Compare(version, prerelease) > 0 {
+			prerelease = version
+		}
+	}
+
+	if excludePrereleases {
+		return latest, ""
+	}
+	if latest == "" {
+		return prerelease, ""
+	}
+	if semver.Compare(prerelease, latest) < 0 {
+		prerelease = ""
+	}
+	return latest, prerelease
+}
+
+// installedRevision returns the revision to look up the installed version of a dependency by. Version tags of a module
+// in a subdirectory of its repository carry the directory as a prefix, like the tags its latest version is taken from.
+func installedRevision(dep models.Dependency) string {
+	if semver.IsValid(dep.Revision) {
+		return tagPrefix(dep) + dep.Revision
+	}
+	return dep.Revision
+}
+
+// tagPrefix returns the prefix of the version tags of a dependency, the directory of the module in its repository
+func tagPrefix(dep models.Dependency) string {
+	if dep.Subdir == "" {
+		return ""
+	}
+	return dep.Subdir + "/"
+}
@@ -0,0 +1,158 @@
+package versioncontrol
+
+import (
+	"testing"
+
+	"github.com/1Password/dep-report/models"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/semver"
+)
+
+func TestLatestTagged(t *testing.T) {
+	tests := []struct {
+		description        string
+		tags               []string
+		prefix             string
+		excludePrereleases bool
+		wantLatest         string
+		wantPrerelease     string
+	}{
+		{
+			description: "should pick the highest version rather than the last tag",
+			tags:        []string{"v0.1.0", "v0.10.0", "v0.2.0", "v0.9.0"},
+			wantLatest:  "v0.10.0",
+		},
+		{
+			description: "should ignore tags that are not semantic versions",
+			tags:        []string{"v1.0.0", "release-2", "2.0.0", "v2", "latest"},
+			wantLatest:  "v1.0.0",
+		},
+		{
+			description:    "should report a newer prerelease separately",
+			tags:           []string{"v1.0.0", "v1.1.0-rc.1", "v0.9.0-beta"},
+			wantLatest:     "v1.0.0",
+			wantPrerelease: "v1.1.0-rc.1",
+		},
+		{
+			description:        "should leave out prereleases when they are excluded",
+			tags:               []string{"v1.0.0", "v1.1.0-rc.1"},
+			excludePrereleases: true,
+			wantLatest:         "v1.0.0",
+		},
+		{
+			description: "should fall back to the latest prerelease when nothing is released",
+			tags:        []string{"v1.0.0-alpha", "v1.0.0-beta"},
+			wantLatest:  "v1.0.0-beta",
+		},
+		{
+			description:        "should report no version when only prereleases are tagged and they are excluded",
+			tags:               []string{"v1.0.0-alpha", "v1.0.0-beta"},
+			excludePrereleases: true,
+		},
+		{
+			description:    "should only consider the tags of the module directory",
+			tags:           []string{"v0.56.0", "bigquery/v1.6.0", "pubsub/v1.3.1", "pubsub/v1.3.2-beta", "firestore/1.1.1"},
+			prefix:         "pubsub/",
+			wantLatest:     "v1.3.1",
+			wantPrerelease: "v1.3.2-beta",
+		},
+		{
+			description: "should not mistake the tags of other modules for those of the repository root",
+			tags:        []string{"v0.56.0", "bigquery/v1.6.0"},
+			wantLatest:  "v0.56.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			latest, prerelease := latestTagged(test.tags, test.prefix, test.excludePrereleases)
+			assert.Equal(t, test.wantLatest, latest)
+			assert.Equal(t, test.wantPrerelease, prerelease)
+		})
+	}
+}
+
+func TestInstalledRevision(t *testing.T) {
+	tests := []struct {
+		description  string
+		dependency   models.Dependency
+		wantRevision string
+	}{
+		{
+			description:  "should prefix version tags with the module directory",
+			dependency:   models.Dependency{Revision: "v1.30.1", Subdir: "storage"},
+			wantRevision: "storage/v1.30.1",
+		},
+		{
+			description:  "should keep version tags of modules at the repository root",
+			dependency:   models.Dependency{Revision: "v1.30.1"},
+			wantRevision: "v1.30.1",
+		},
+		{
+			description:  "should keep commit hashes",
+			dependency:   models.Dependency{Revision: "c537b5101cb9", Subdir: "storage"},
+			wantRevision: "c537b5101cb9",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.wantRevision, installedRevision(test.dependency))
+		})
+	}
+}
+
+func TestGithubTags(t *testing.T) {
+	skipUnrecorded(t, "githubTags")
+	r, c, err := SetupHTTPRecord("githubTags")
+	if err != nil {
+		t.Fatalf("unable to setup http recorder, %v", err)
+	}
+	defer r.Stop()
+
+	request := Client{HttpClient: c}
+
+	// grpc-go has tagged more releases than fit a page, next to the releases of the modules in its subdirectories
+	dependency := models.Dependency{Name: "github.com/grpc/grpc-go", Repo: "https://github.com/grpc/grpc-go"}
+	tags, err := GithubProvider{}.Tags(dependency, request)
+	if err != nil {
+		t.Fatalf("error returned from Tags, err: %v", err)
+	}
+	assert.Greater(t, len(tags), tagsPerPage)
+	assert.Contains(t, tags, "v1.50.0")
+	assert.Contains(t, tags, "cmd/protoc-gen-go-grpc/v1.3.0")
+
+	dependency = models.Dependency{
+		Name:   "google.golang.org/grpc/cmd/protoc-gen-go-grpc",
+		Repo:   "https://github.com/grpc/grpc-go",
+		Subdir: "cmd/protoc-gen-go-grpc",
+	}
+	reportObject, err := ReportObjFromProvider(tagsOnlyProvider{GithubProvider{}}, dependency, request)
+	if assert.NoError(t, err) {
+		assert.True(t, semver.Compare(reportObject.Latest.Version, "v1.6.2") >= 0,
+			"latest %q should not be older than v1.6.2", reportObject.Latest.Version)
+		rootLatest, _ := latestTagged(tags, "", true)
+		assert.NotEqual(t, rootLatest, reportObject.Latest.Version, "the tags of grpc itself leaked into the module")
+	}
+}
+
+// tagsOnlyProvider resolves nothing but the tags of a GitHub repository
+type tagsOnlyProvider struct {
+	GithubProvider
+}
+
+func (tagsOnlyProvider) Website(dep models.Dependency, r Client) (string, error) {
+	return "", nil
+}
+
+func (tagsOnlyProvider) License(dep models.Dependency, r Client) (string, error) {
+	return "", nil
+}
+
+func (tagsOnlyProvider) Installed(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	return models.VersionDetails{}, nil
+}
+
+func (tagsOnlyProvider) Latest(dep models.Dependency, r Client) (models.VersionDetails, error) {
+	return models.VersionDetails{}, nil
+}
@@ -0,0 +1,158 @@
+package versioncontrol
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RepoRoot describes the repository behind an import path as advertised by its go-get meta tags
+// https://go.dev/ref/mod#vcs-find
+type RepoRoot struct {
+	// Prefix is the import path prefix served by the repository
+	Prefix string
+	// VCS is the version control system used by the repository, e.g. git
+	VCS string
+	// RepoURL is the URL of the repository root
+	RepoURL string
+	// Home is the website of the repository from the go-source meta tag, if any
+	Home string
+}
+
+type metaImport struct {
+	prefix, vcs, repoURL string
+}
+
+type metaSource struct {
+	prefix, home string
+}
+
+// ResolveImportPath finds the repository hosting an import path by fetching https://<path>?go-get=1
+// and reading the go-import and go-source meta tags. Like the go command, shorter prefixes of the
+// path are tried in turn until a server answers with a go-import tag matching the path.
+func (r *Client) ResolveImportPath(importPath string) (*RepoRoot, error) {
+	elems := strings.Split(importPath, "/")
+	for i := len(elems); i >= 2; i-- {
+		prefix := strings.Join(elems[:i], "/")
+
+		imports, sources, err := r.getMetaTags(prefix)
+		if err != nil && unreachable(err) {
+			// Every prefix lives on the same host
+			return nil, errors.Wrapf(err, "unable to find go-import meta tag for %s", importPath)
+		}
+		if err != nil {
+			continue
+		}
+
+		root, ok := matchMetaImport(importPath, imports)
+		if !ok {
+			continue
+		}
+		for _, source := range sources {
+			if source.prefix == root.Prefix {
+				root.Home = source.home
+			}
+		}
+		return root, nil
+	}
+
+	return nil, fmt.Errorf("unable to find go-import meta tag for %s", importPath)
+}
+
+// getMetaTags fetches the go-get page of an import path like any other API response, so that it is cached,
+// retried and never fetched in cache-only mode
+func (r *Client) getMetaTags(importPath string) ([]metaImport, []metaSource, error) {
+	url := "https://" + importPath + "?go-get=1"
+	body, err := r.get(url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return parseMetaTags(bytes.NewReader(body))
+}
+
+// matchMetaImport picks the go-import tag whose prefix covers the import path.
+// Entries using the "mod" pseudo-VCS only point at a module proxy, so a real VCS is preferred.
+func matchMetaImport(importPath string, imports []metaImport) (*RepoRoot, bool) {
+	var match *RepoRoot
+	for _, imp := range imports {
+		if importPath != imp.prefix && !strings.HasPrefix(importPath, imp.prefix+"/") {
+			continue
+		}
+		if match != nil && imp.vcs == "mod" {
+			continue
+		}
+		match = &RepoRoot{
+			Prefix:  imp.prefix,
+			VCS:     imp.vcs,
+			RepoURL: strings.TrimSuffix(imp.repoURL, ".git"),
+		}
+	}
+	return match, match != nil
+}
+
+// parseMetaTags reads the go-import and go-source meta tags from the head of an html page.
+// The decoder is lenient because these pages are rarely valid xml.
+func parseMetaTags(r io.Reader) ([]metaImport, []metaSource, error) {
+	d := xml.NewDecoder(r)
+	d.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(charset) {
+		case "utf-8", "ascii":
+			return input, nil
+		default:
+			return nil, fmt.Errorf("can't decode XML document using charset %q", charset)
+		}
+	}
+	d.Strict = false
+
+	var imports []metaImport
+	var sources []metaSource
+	for {
+		t, err := d.RawToken()
+		if err != nil {
+			if err == io.EOF || len(imports) > 0 {
+				break
+			}
+			return nil, nil, errors.Wrap(err, "unable to parse go-get page")
+		}
+
+		if e, ok := t.(xml.StartElement); ok && strings.EqualFold(e.Name.Local, "body") {
+			break
+		}
+		if e, ok := t.(xml.EndElement); ok && strings.EqualFold(e.Name.Local, "head") {
+			break
+		}
+
+		e, ok := t.(xml.StartElement)
+		if !ok || !strings.EqualFold(e.Name.Local, "meta") {
+			continue
+		}
+
+		content := strings.Fields(attrValue(e.Attr, "content"))
+		switch attrValue(e.Attr, "name") {
+		case "go-import":
+			if len(content) == 3 {
+				imports = append(imports, metaImport{prefix: content[0], vcs: content[1], repoURL: content[2]})
+			}
+		case "go-source":
+			if len(content) == 4 {
+				sources = append(sources, metaSource{prefix: content[0], home: content[1]})
+			}
+		}
+	}
+
+	return imports, sources, nil
+}
+
+func attrValue(attrs []xml.Attr, name string) string {
+	for _, a := range attrs {
+		if strings.EqualFold(a.Name.Local, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
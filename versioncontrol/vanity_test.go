@@ -0,0 +1,196 @@
+package versioncontrol
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rewriteTransport sends every request to a test server, keeping the original host in the path
+type rewriteTransport struct {
+	server *httptest.Server
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, err := url.Parse(t.server.URL)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.Path = "/" + req.URL.Host + req.URL.Path
+	req.URL.Scheme = target.Scheme
+	req.URL.Host = target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestResolveImportPath(t *testing.T) {
+	pages := map[string]string{
+		"/go.uber.org/zap": `<!DOCTYPE html>
+<html>
+<head>
+<meta name="go-import" content="go.uber.org/zap git https://github.com/uber-go/zap">
+<meta name="go-source" content="go.uber.org/zap https://github.com/uber-go/zap https://github.com/uber-go/zap/tree/master{/dir} https://github.com/uber-go/zap/tree/master{/dir}/{file}#L{line}">
+</head>
+<body>
+<meta name="go-import" content="go.uber.org/zap git https://example.com/ignored">
+</body>
+</html>`,
+		"/k8s.io/client-go": `<html><head>
+<meta name="go-import" content="k8s.io/client-go mod https://proxy.example.com">
+<meta name="go-import" content="k8s.io/client-go git https://github.com/kubernetes/client-go.git">
+</head></html>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("go-get") != "1" {
+			http.Error(w, "missing go-get", http.StatusBadRequest)
+			return
+		}
+		page, ok := pages[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	request := Client{
+		HttpClient: &http.Client{Transport: rewriteTransport{server: server}},
+	}
+
+	tests := []struct {
+		description  string
+		importPath   string
+		wantRepoRoot *RepoRoot
+		wantError    bool
+	}{
+		{
+			description: "should resolve repo root and website from meta tags in the head",
+			importPath:  "go.uber.org/zap",
+			wantRepoRoot: &RepoRoot{
+				Prefix:  "go.uber.org/zap",
+				VCS:     "git",
+				RepoURL: "https://github.com/uber-go/zap",
+				Home:    "https://github.com/uber-go/zap",
+			},
+		},
+		{
+			description: "should walk up to the prefix serving meta tags and prefer a real vcs over mod",
+			importPath:  "k8s.io/client-go/kubernetes/scheme",
+			wantRepoRoot: &RepoRoot{
+				Prefix:  "k8s.io/client-go",
+				VCS:     "git",
+				RepoURL: "https://github.com/kubernetes/client-go",
+			},
+		},
+		{
+			description: "should fail when no prefix serves meta tags",
+			importPath:  "example.com/unknown/pkg",
+			wantError:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			root, err := request.ResolveImportPath(test.importPath)
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			if err != nil {
+				t.Errorf("error returned from ResolveImportPath, err: %v", err)
+			}
+			assert.EqualValues(t, test.wantRepoRoot, root)
+		})
+	}
+}
+
+func TestResolveImportPathCache(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`<html><head><meta name="go-import" content="go.uber.org/zap git https://github.com/uber-go/zap"></head></html>`))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "dep-report-cache")
+	if err != nil {
+		t.Fatalf("unable to create cache dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	request := Client{
+		HttpClient: &http.Client{Transport: rewriteTransport{server: server}},
+		Cache:      &Cache{Dir: dir, Offline: true},
+	}
+	_, err = request.ResolveImportPath("go.uber.org/zap")
+	assert.Error(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests), "should not use the network in cache-only mode")
+
+	request.Cache = &Cache{Dir: dir, TTL: time.Hour}
+	for i := 0; i < 2; i++ {
+		root, err := request.ResolveImportPath("go.uber.org/zap")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://github.com/uber-go/zap", root.RepoURL)
+		}
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "should serve the go-get page from the cache")
+}
+
+// failingTransport fails every request with the same error
+type failingTransport struct {
+	err      error
+	requests *int32
+}
+
+func (t failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(t.requests, 1)
+	return nil, t.err
+}
+
+func TestResolveImportPathUnreachable(t *testing.T) {
+	tests := []struct {
+		description  string
+		err          error
+		wantRequests int32
+	}{
+		{
+			description:  "should give up on hosts that do not resolve",
+			err:          &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "example.com", IsNotFound: true}},
+			wantRequests: 1,
+		},
+		{
+			description:  "should give up on hosts that refuse connections",
+			err:          &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			wantRequests: 1,
+		},
+		{
+			description:  "should retry temporary lookup failures",
+			err:          &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "server misbehaving", Name: "example.com", IsTemporary: true}},
+			wantRequests: 2 * defaultMaxAttempts,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var requests int32
+			request := Client{
+				HttpClient: &http.Client{Transport: failingTransport{err: test.err, requests: &requests}},
+				Retry:      Retry{BaseDelay: time.Millisecond},
+			}
+
+			_, err := request.ResolveImportPath("example.com/a/b")
+			assert.Error(t, err)
+			// Unreachable hosts end the walk, other failures move on from example.com/a/b to example.com/a
+			assert.Equal(t, test.wantRequests, atomic.LoadInt32(&requests))
+		})
+	}
+}
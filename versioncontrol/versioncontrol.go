@@ -1,9 +1,60 @@
 package versioncontrol
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"time"
 
-//Client holds the necessary items to make api calls to various version control providers
+	"github.com/pkg/errors"
+)
+
+// Client holds the necessary items to make api calls to various version control providers
 type Client struct {
 	HttpClient *http.Client
-	Token string
+	Token      string
+	//GoProxy configures the module proxies used to resolve versions of modules hosted elsewhere
+	GoProxy GoProxy
+	//Gitlab configures access to gitlab.com and self-hosted GitLab instances
+	Gitlab Gitlab
+	//Bitbucket configures access to bitbucket.org and self-hosted Bitbucket Server
+	Bitbucket Bitbucket
+	//Gitea configures the Gitea, Forgejo and Codeberg instances to query
+	Gitea Gitea
+	//Retry configures how failed and rate limited requests are retried
+	Retry Retry
+	//ModCache locates the module cache used to resolve dependencies offline
+	ModCache ModCache
+	//Cache stores responses on disk between runs, nil to always use the network
+	Cache *Cache
+	//ExcludePrereleases keeps prerelease tags from being reported as the latest version
+	ExcludePrereleases bool
+
+	ctx context.Context
+}
+
+// WithContext returns a copy of the client whose requests are cancelled when ctx is done
+func (r Client) WithContext(ctx context.Context) Client {
+	r.ctx = ctx
+	return r
+}
+
+// newRequest creates a GET request bound to the context of the client
+func (r *Client) newRequest(url string) (*http.Request, error) {
+	return http.NewRequestWithContext(r.context(), "GET", url, nil)
+}
+
+func (r *Client) context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// formatRFC3339Time converts the RFC 3339 timestamps returned by most APIs to the UTC format used in the report
+func formatRFC3339Time(t string) (string, error) {
+	t1, err := time.Parse(time.RFC3339, t)
+	if err != nil {
+		return "", errors.Wrapf(err, "Unable to parse time %s", t)
+	}
+	return t1.UTC().Format("2006-01-02T15:04:05Z"), nil
 }
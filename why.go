@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/1Password/dep-report/parse"
+)
+
+// runWhy implements `dep-report why <module>...`, printing the shortest requirement chains from the main module
+// to each module in the style of `go mod why -m`
+func runWhy(args []string) {
+	flags := flag.NewFlagSet("why", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: dep-report why <module>...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("unable to get working directory: %v", err)
+	}
+	graph, err := parse.ReadModGraph(wd)
+	if err != nil {
+		log.Fatalf("unable to read module graph: %v", err)
+	}
+
+	printWhy(os.Stdout, graph, flags.Args())
+}
+
+func printWhy(w io.Writer, graph *parse.ModuleGraph, paths []string) {
+	for i, path := range paths {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "# %s\n", path)
+
+		chains := graph.Why(path)
+		if len(chains) == 0 {
+			fmt.Fprintf(w, "(main module does not need module %s)\n", path)
+			continue
+		}
+		for j, chain := range chains {
+			if j > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintln(w, strings.Join(chain, "\n"))
+		}
+	}
+}
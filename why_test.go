@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/1Password/dep-report/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintWhy(t *testing.T) {
+	graph, err := parse.ParseModGraph(strings.NewReader(`example.com/main example.com/a@v1.0.0
+example.com/main example.com/b@v1.0.0
+example.com/a@v1.0.0 example.com/shared@v0.1.0
+example.com/b@v1.0.0 example.com/shared@v0.1.0
+`))
+	if err != nil {
+		t.Fatalf("unable to parse module graph: %v", err)
+	}
+
+	var out bytes.Buffer
+	printWhy(&out, graph, []string{"example.com/shared", "example.com/a", "example.com/unused"})
+	assert.Equal(t, `# example.com/shared
+example.com/main
+example.com/a@v1.0.0
+example.com/shared@v0.1.0
+
+example.com/main
+example.com/b@v1.0.0
+example.com/shared@v0.1.0
+
+# example.com/a
+example.com/main
+example.com/a@v1.0.0
+
+# example.com/unused
+(main module does not need module example.com/unused)
+`, out.String())
+}